@@ -34,10 +34,47 @@ type RedisConfig struct {
 type ServiceConfig struct {
 	TslCrt       string                    `json:"tslCrt"`
 	TslKey       string                    `json:"tslKey"`
+	CryptoKey    string                    `json:"cryptoKey"` //密钥交换时服务器签名使用的Ed25519私钥(PEM)
 	ServiceNodes map[int]ServiceNodeConfig `json:"services"`
 }
 
 type ServiceNodeConfig struct {
-	ClientPort string `json:"clientPort"`
-	UseSSL     bool   `json:"useSSL"`
+	ClientPort         string   `json:"clientPort"`
+	ClientType         string   `json:"clientType"`
+	SocketPort         string   `json:"socketPort"`     //Socket端口，与其他前端端口可同时配置，为空时使用clientType对应的clientPort
+	WebSocketPort      string   `json:"webSocketPort"`  //WebSocket端口
+	KcpPort            string   `json:"kcpPort"`        //Kcp端口
+	WebSocketPath      string   `json:"webSocketPath"`  //WebSocket路径，默认为/
+	AllowOrigins       []string `json:"allowOrigins"`   //WebSocket允许的Origin，为空时只允许同源，*为允许所有
+	ProxyProtocol      bool     `json:"proxyProtocol"`  //Socket连接带有PROXY协议头(v1或v2)，负载均衡开启后才能配置
+	TrustedProxies     []string `json:"trustedProxies"` //WebSocket可信代理的IP或网段，来自这些地址的请求使用X-Forwarded-For
+	UseSSL             bool     `json:"useSSL"`
+	SocketSSL          bool     `json:"socketSSL"`          //Socket端口(socketPort)使用TLS，使用clientPort时为useSSL
+	WebSocketSSL       bool     `json:"webSocketSSL"`       //WebSocket端口(webSocketPort)使用TLS，使用clientPort时为useSSL
+	UseCrypto          bool     `json:"useCrypto"`          //客户端需进行密钥交换，消息使用AES-GCM加密
+	MaxFrameSize       int      `json:"maxFrameSize"`       //单条消息最大长度(字节)，0为默认值
+	CompressThreshold  int      `json:"compressThreshold"`  //消息压缩阈值(字节)，客户端握手协商压缩后生效
+	SendQueueSize      int      `json:"sendQueueSize"`      //每个Session发送队列长度，0为默认值
+	SendQueuePolicy    string   `json:"sendQueuePolicy"`    //发送队列满时的处理方式：drop(丢弃消息)、disconnect(断开连接)
+	ResumeGrace        int      `json:"resumeGrace"`        //断线后保留Session等待重连的时间(秒)，0为不开启
+	MaxSessions        int      `json:"maxSessions"`        //最大连接数，0为不限制
+	MaxConnPerIp       int      `json:"maxConnPerIp"`       //单个IP最大连接数，0为不限制
+	ConnRatePerIp      float64  `json:"connRatePerIp"`      //单个IP每秒允许的新建连接数，0为不限制
+	ConnBurstPerIp     float64  `json:"connBurstPerIp"`     //单个IP允许的突发新建连接数
+	PingTimeout        int      `json:"pingTimeout"`        //心跳超时时间(秒)，0为默认15秒
+	PingInterval       int      `json:"pingInterval"`       //心跳超时检测间隔(秒)，0为默认2秒
+	ServerPingInterval int      `json:"serverPingInterval"` //服务器主动ping客户端的间隔(秒)，用于统计RTT，0为不开启
+	JsonDebug          bool     `json:"jsonDebug"`          //WebSocket允许使用JSON调试子协议，正式环境需关闭
+	Weight             int      `json:"weight"`             //一致性哈希权重，注册到consul，0为默认值1
+	UserRouteTtl       int      `json:"userRouteTtl"`       //Redis中用户所在服务器的保留时间(秒)，在线期间自动续期，0为默认300秒
+}
+
+// RouteConfig connector将消息ID范围内的消息转发到对应的后端服务
+type RouteConfig struct {
+	Service      string            `json:"service"`
+	MinMsgId     uint16            `json:"minMsgId"`
+	MaxMsgId     uint16            `json:"maxMsgId"`
+	StickyKeys   map[uint16]string `json:"stickyKeys"`   //消息ID对应的字段，按该字段的值分配服务器，其他消息发送到已分配的服务器
+	RequireLogin bool              `json:"requireLogin"` //需要登录后才能发送
+	UserRoute    bool              `json:"userRoute"`    //按登录绑定的用户ID分配服务器，不使用StickyKeys，用户所在服务器记录到Redis，重连到其他connector后仍发送到该服务器
 }
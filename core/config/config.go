@@ -17,6 +17,7 @@ var (
 	logConfig     LogConfig
 	mysqlConfig   map[string]MysqlConfig
 	mongoConfig   map[string]MongoConfig
+	routeConfig   []RouteConfig
 	lock          sync.Mutex
 )
 
@@ -32,6 +33,7 @@ func load() {
 	loadConfig(&mysqlConfig, "mysql.json")
 	loadConfig(&mongoConfig, "mongo.json")
 	loadConfig(&logConfig, "log.json")
+	loadConfig(&routeConfig, "route.json")
 	lock.Unlock()
 }
 
@@ -68,3 +70,7 @@ func GetLogConfig() LogConfig {
 func GetMongoConfig() map[string]MongoConfig {
 	return mongoConfig
 }
+
+func GetRouteConfig() []RouteConfig {
+	return routeConfig
+}
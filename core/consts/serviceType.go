@@ -3,6 +3,7 @@ package consts
 const (
 	ServiceType_Socket    = "socket"
 	ServiceType_WebSocket = "webSocket"
+	ServiceType_Kcp       = "kcp"
 	ServiceType_Http      = "http"
 	ServiceType_Rpc       = "rpc"
 	ServiceType_Ipc       = "ipc"
@@ -0,0 +1,165 @@
+package admission
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/yicaoyimuys/GoGameServer/core/libs/consul"
+	"github.com/yicaoyimuys/GoGameServer/core/libs/iplist"
+	"github.com/yicaoyimuys/GoGameServer/core/libs/logger"
+	"github.com/yicaoyimuys/GoGameServer/core/libs/ratelimit"
+	"github.com/yicaoyimuys/GoGameServer/core/libs/stack"
+	"github.com/yicaoyimuys/GoGameServer/core/libs/timer"
+	"go.uber.org/zap"
+)
+
+const (
+	//consul KV中的黑名单，格式与FilterServices相同，例如: 1.2.3.4;10.0.0.0/8
+	denyIpsKey = "DenyIps"
+	//IP状态在空闲多久后清理(秒)
+	ipIdleTime = 60
+)
+
+var (
+	ErrDenied       = errors.New("admission: ip denied")
+	ErrMaxSessions  = errors.New("admission: too many sessions")
+	ErrMaxConnPerIp = errors.New("admission: too many connections from ip")
+	ErrConnRate     = errors.New("admission: connect too frequently")
+)
+
+type ipState struct {
+	connNum  int
+	bucket   *ratelimit.TokenBucket
+	lastTime int64
+}
+
+// Admission 连接准入控制，多个前端Server共用时总连接数一起计算
+type Admission struct {
+	maxSessions    int
+	maxConnPerIp   int
+	connRatePerIp  float64
+	connBurstPerIp float64
+
+	sessionNum int
+	ips        map[string]*ipState
+	mutex      sync.Mutex
+
+	denyIps    atomic.Value
+	denyConfig string
+}
+
+// NewAdmission 参数为0时表示不限制
+func NewAdmission(maxSessions int, maxConnPerIp int, connRatePerIp float64, connBurstPerIp float64) *Admission {
+	if connRatePerIp > 0 && connBurstPerIp < 1 {
+		connBurstPerIp = 1
+	}
+	admission := &Admission{
+		maxSessions:    maxSessions,
+		maxConnPerIp:   maxConnPerIp,
+		connRatePerIp:  connRatePerIp,
+		connBurstPerIp: connBurstPerIp,
+		ips:            make(map[string]*ipState),
+	}
+	denyIps, _ := iplist.NewIpList(nil)
+	admission.denyIps.Store(denyIps)
+	return admission
+}
+
+// Start 加载黑名单，之后每10秒检测一次更新
+func (this *Admission) Start() {
+	err := consul.InitKV(true)
+	stack.CheckError(err)
+
+	this.loadDenyIps()
+	timer.DoTimer(10*1000, this.loadDenyIps)
+	timer.DoTimer(ipIdleTime*1000, this.clearIdleIps)
+}
+
+// Accept 检测是否允许连接，允许时返回的release需在连接断开时调用
+func (this *Admission) Accept(remoteAddr string) (func(), error) {
+	remoteIp := iplist.ParseIp(remoteAddr)
+	if remoteIp == nil {
+		return nil, ErrDenied
+	}
+	if this.denyIps.Load().(*iplist.IpList).Contains(remoteIp) {
+		return nil, ErrDenied
+	}
+	ip := remoteIp.String()
+
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	state, ok := this.ips[ip]
+	if !ok {
+		state = &ipState{}
+		if this.connRatePerIp > 0 {
+			state.bucket = ratelimit.NewTokenBucket(this.connRatePerIp, this.connBurstPerIp)
+		}
+		this.ips[ip] = state
+	}
+	state.lastTime = time.Now().Unix()
+
+	if state.bucket != nil && !state.bucket.Allow() {
+		return nil, ErrConnRate
+	}
+	if this.maxConnPerIp > 0 && state.connNum >= this.maxConnPerIp {
+		return nil, ErrMaxConnPerIp
+	}
+	if this.maxSessions > 0 && this.sessionNum >= this.maxSessions {
+		return nil, ErrMaxSessions
+	}
+
+	state.connNum++
+	this.sessionNum++
+
+	var once sync.Once
+	release := func() {
+		once.Do(func() {
+			this.release(ip)
+		})
+	}
+	return release, nil
+}
+
+func (this *Admission) release(ip string) {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	this.sessionNum--
+	if state, ok := this.ips[ip]; ok {
+		state.connNum--
+		state.lastTime = time.Now().Unix()
+	}
+}
+
+func (this *Admission) clearIdleIps() {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	nowTime := time.Now().Unix()
+	for ip, state := range this.ips {
+		if state.connNum <= 0 && nowTime-state.lastTime >= ipIdleTime {
+			delete(this.ips, ip)
+		}
+	}
+}
+
+func (this *Admission) loadDenyIps() {
+	defer stack.TryError()
+
+	value := consul.KV_Get(denyIpsKey)
+	if value == this.denyConfig {
+		return
+	}
+	this.denyConfig = value
+
+	denyIps, invalid := iplist.NewIpList(strings.Split(value, ";"))
+	for _, item := range invalid {
+		logger.Error("黑名单格式错误", zap.String("Item", item))
+	}
+	this.denyIps.Store(denyIps)
+	logger.Info("黑名单已更新", zap.Int("Num", denyIps.Len()))
+}
@@ -24,6 +24,12 @@ type ServiceInfo struct {
 	Address string
 	Port    string
 	SortKey string
+	Weight  int //一致性哈希权重，注册时未设置为1
+}
+
+// Addr 服务地址
+func (this ServiceInfo) Addr() string {
+	return this.Address + ":" + this.Port
 }
 
 func NewClient() (*Client, error) {
@@ -72,6 +78,15 @@ func getFilterServices() []string {
 }
 
 func (this *Client) GetServices(service string) []string {
+	results := []string{}
+	for _, data := range this.GetServiceInfos(service) {
+		results = append(results, data.Addr())
+	}
+	return results
+}
+
+// GetServiceInfos 获取健康的服务列表，按SortKey排序
+func (this *Client) GetServiceInfos(service string) []ServiceInfo {
 	// 最多重试 10 次，每次等待 1 秒
 	maxRetries := 10
 	for i := 0; i < maxRetries; i++ {
@@ -86,12 +101,17 @@ func (this *Client) GetServices(service string) []string {
 
 				arr := strings.Split(entry.Service.ID, "-")
 				serviveId := arr[2]
+				weight := cast.ToInt(entry.Service.Meta[MetaWeight])
+				if weight < 1 {
+					weight = 1
+				}
 				data := ServiceInfo{
 					ID:      entry.Service.ID,
 					Name:    entry.Service.Service,
 					Address: entry.Service.Address,
 					Port:    cast.ToString(entry.Service.Port),
 					SortKey: entry.Service.Address + "-" + serviveId,
+					Weight:  weight,
 				}
 				serviceDatas = append(serviceDatas, data)
 			}
@@ -103,14 +123,7 @@ func (this *Client) GetServices(service string) []string {
 					return serviceDatas[i].SortKey < serviceDatas[j].SortKey
 				})
 
-				//组装返回数据
-				results := []string{}
-				for i := 0; i < len(serviceDatas); i++ {
-					data := serviceDatas[i]
-					addr := data.Address + ":" + data.Port
-					results = append(results, addr)
-				}
-				return results
+				return serviceDatas
 			}
 		}
 
@@ -121,7 +134,7 @@ func (this *Client) GetServices(service string) []string {
 
 	// 如果重试次数用完还没找到服务，返回空列表
 	logger.Error("无法找到服务", zap.String("ServiceName", service))
-	return []string{}
+	return []ServiceInfo{}
 }
 
 func (this *Client) DeregisterService(serviceID string) {
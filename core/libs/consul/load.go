@@ -0,0 +1,71 @@
+package consul
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+const (
+	//负载在KV中的路径：ServiceLoad/服务名/服务地址
+	loadKeyPrefix = "ServiceLoad/"
+	//超过该时间未上报的负载视为无效(秒)
+	loadExpireTime = 15
+
+	//负载分数中每个等待处理的消息、每个CPU核心占满相当于的Session数
+	loadQueueFactor = 10
+	loadCpuFactor   = 1000
+)
+
+// ServiceLoad 后端服务定时上报的负载
+type ServiceLoad struct {
+	Sessions int     `json:"sessions"` //Session数量
+	Cpu      float64 `json:"cpu"`      //CPU使用率，1为占满一个核心
+	Queue    int     `json:"queue"`    //等待处理的消息数量
+	Time     int64   `json:"time"`     //上报时间(秒)
+}
+
+// Score 负载分数，weight为服务的一致性哈希权重，权重越高可承受的负载越高
+func (this *ServiceLoad) Score(weight int) float64 {
+	if weight < 1 {
+		weight = 1
+	}
+	score := float64(this.Sessions) + float64(this.Queue)*loadQueueFactor + this.Cpu*loadCpuFactor
+	return score / float64(weight)
+}
+
+func loadKey(serviceName string, serviceAddr string) string {
+	return loadKeyPrefix + serviceName + "/" + serviceAddr
+}
+
+// SetServiceLoad 上报本进程的负载
+func SetServiceLoad(serviceName string, serviceAddr string, load *ServiceLoad) error {
+	data, err := json.Marshal(load)
+	if err != nil {
+		return err
+	}
+	return KV_Set(loadKey(serviceName, serviceAddr), string(data))
+}
+
+// GetServiceLoads 获取服务所有节点最近上报的负载，key为服务地址
+func GetServiceLoads(serviceName string) map[string]*ServiceLoad {
+	loads := make(map[string]*ServiceLoad)
+	prefix := loadKey(serviceName, "")
+	pairs, _, err := kv.List(prefix, nil)
+	if err != nil {
+		return loads
+	}
+
+	now := time.Now().Unix()
+	for _, pair := range pairs {
+		var load ServiceLoad
+		if json.Unmarshal(pair.Value, &load) != nil {
+			continue
+		}
+		if now-load.Time > loadExpireTime {
+			continue
+		}
+		loads[strings.TrimPrefix(pair.Key, prefix)] = &load
+	}
+	return loads
+}
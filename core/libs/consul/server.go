@@ -7,13 +7,67 @@ import (
 	"time"
 
 	"github.com/yicaoyimuys/GoGameServer/core/libs/logger"
+	"github.com/yicaoyimuys/GoGameServer/core/libs/timer"
 	"go.uber.org/zap"
 
 	"github.com/hashicorp/consul/api"
 	"github.com/spf13/cast"
 )
 
+// MetaWeight 服务注册Meta中的一致性哈希权重
+const MetaWeight = "weight"
+
+var serviceWeight = 1
+
+// SetServiceWeight 设置本进程注册服务的一致性哈希权重，需在注册服务前调用
+func SetServiceWeight(weight int) {
+	if weight < 1 {
+		weight = 1
+	}
+	serviceWeight = weight
+}
+
 func NewServive(serviceAddress string, serviceName string, serviceId int, servicePort string) error {
+	//健康检查配置
+	checkPath := serviceAddress + ":" + servicePort
+	check := &api.AgentServiceCheck{
+		TCP:                            checkPath,
+		Timeout:                        "1s",
+		Interval:                       "3s",
+		DeregisterCriticalServiceAfter: "10s", //check失败后10秒删除本服务
+	}
+
+	_, _, err := registerServive(serviceAddress, serviceName, serviceId, servicePort, check)
+	return err
+}
+
+// NewUdpServive UDP端口consul无法进行TCP检查，改为本进程定时上报TTL
+func NewUdpServive(serviceAddress string, serviceName string, serviceId int, servicePort string) error {
+	//健康检查配置
+	check := &api.AgentServiceCheck{
+		TTL:                            "10s",
+		Status:                         api.HealthPassing,
+		DeregisterCriticalServiceAfter: "10s", //check失败后10秒删除本服务
+	}
+
+	client, id, err := registerServive(serviceAddress, serviceName, serviceId, servicePort, check)
+	if err != nil {
+		return err
+	}
+
+	//定时上报健康状态
+	checkId := "service:" + id
+	timer.DoTimer(3*1000, func() {
+		err := client.Agent().UpdateTTL(checkId, "", api.HealthPassing)
+		if err != nil {
+			logger.Error("Failed to update service ttl", zap.String("CheckId", checkId), zap.Error(err))
+		}
+	})
+
+	return nil
+}
+
+func registerServive(serviceAddress string, serviceName string, serviceId int, servicePort string, check *api.AgentServiceCheck) (*api.Client, string, error) {
 	// 添加重试机制
 	maxRetries := 5
 	var client *api.Client
@@ -31,21 +85,18 @@ func NewServive(serviceAddress string, serviceName string, serviceId int, servic
 	}
 
 	if err != nil {
-		return err
+		return nil, "", err
 	}
 
 	//服务器配置
 	address := serviceAddress
 	port, err := strconv.Atoi(servicePort)
 	if err != nil {
-		return err
+		return nil, "", err
 	}
 	id := address + ":" + servicePort + "-" + serviceName + "-" + cast.ToString(serviceId)
 	name := serviceName
 
-	//健康检查配置
-	checkPath := address + ":" + servicePort
-
 	//服务注册
 	service := &api.AgentServiceRegistration{
 		ID:      id,
@@ -53,12 +104,8 @@ func NewServive(serviceAddress string, serviceName string, serviceId int, servic
 		Address: address,
 		Port:    port,
 		Tags:    []string{name},
-		Check: &api.AgentServiceCheck{
-			TCP:                            checkPath,
-			Timeout:                        "1s",
-			Interval:                       "3s",
-			DeregisterCriticalServiceAfter: "10s", //check失败后10秒删除本服务
-		},
+		Meta:    map[string]string{MetaWeight: cast.ToString(serviceWeight)},
+		Check:   check,
 	}
 
 	// 添加服务注册重试机制
@@ -74,13 +121,13 @@ func NewServive(serviceAddress string, serviceName string, serviceId int, servic
 	}
 
 	if err != nil {
-		return err
+		return nil, "", err
 	}
 
 	//关闭处理
 	go WaitToUnRegistService(client, id)
 
-	return nil
+	return client, id, nil
 }
 
 func WaitToUnRegistService(client *api.Client, serviceId string) {
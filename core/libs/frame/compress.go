@@ -0,0 +1,79 @@
+package frame
+
+import (
+	"bytes"
+	"compress/flate"
+	"errors"
+	"io"
+	"sync"
+
+	"github.com/golang/snappy"
+)
+
+// 压缩方式
+const (
+	Compress_None    byte = 0
+	Compress_Deflate byte = 1
+	Compress_Snappy  byte = 2
+)
+
+var errCompress = errors.New("compress type not supported")
+
+var flateWriterPool = sync.Pool{
+	New: func() interface{} {
+		writer, _ := flate.NewWriter(nil, flate.BestSpeed)
+		return writer
+	},
+}
+
+// CheckCompress 检测压缩方式是否支持
+func CheckCompress(compress byte) bool {
+	return compress == Compress_None || compress == Compress_Deflate || compress == Compress_Snappy
+}
+
+func compress(compress byte, data []byte) ([]byte, error) {
+	if compress == Compress_Snappy {
+		return snappy.Encode(nil, data), nil
+	} else if compress == Compress_Deflate {
+		var buf bytes.Buffer
+		writer := flateWriterPool.Get().(*flate.Writer)
+		defer flateWriterPool.Put(writer)
+
+		writer.Reset(&buf)
+		if _, err := writer.Write(data); err != nil {
+			return nil, err
+		}
+		if err := writer.Close(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+	return nil, errCompress
+}
+
+// 解压后的长度同样受maxSize限制
+func decompress(compress byte, data []byte, maxSize int) ([]byte, error) {
+	if compress == Compress_Snappy {
+		msgLen, err := snappy.DecodedLen(data)
+		if err != nil {
+			return nil, err
+		}
+		if msgLen > maxSize {
+			return nil, ErrTooLarge
+		}
+		return snappy.Decode(nil, data)
+	} else if compress == Compress_Deflate {
+		reader := flate.NewReader(bytes.NewReader(data))
+		defer reader.Close()
+
+		result, err := io.ReadAll(io.LimitReader(reader, int64(maxSize)+1))
+		if err != nil {
+			return nil, err
+		}
+		if len(result) > maxSize {
+			return nil, ErrTooLarge
+		}
+		return result, nil
+	}
+	return nil, errCompress
+}
@@ -0,0 +1,192 @@
+package frame
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/binary"
+	"encoding/pem"
+	"errors"
+	"io"
+	"os"
+
+	"golang.org/x/crypto/curve25519"
+	"golang.org/x/crypto/hkdf"
+)
+
+// 密钥交换流程(握手包之后的第一条消息):
+// 1: 客户端发送32字节X25519公钥
+// 2: 服务器回复32字节X25519公钥 + 64字节Ed25519签名(该消息不加密)
+//    签名内容为signContext+客户端公钥+服务器公钥，签名私钥为服务器固定私钥，客户端内置对应公钥进行校验，防止中间人攻击
+// 3: 双方用HKDF-SHA256(共享密钥, salt=客户端公钥+服务器公钥)分别派生c2s、s2c的AES-256-GCM密钥
+// 4: 之后所有消息先压缩后加密，nonce为各方向从0开始递增的计数器，不在消息中传输，消息头作为附加认证数据
+
+const (
+	keyLen         = 32
+	cipherNonce    = 12
+	cipherOverhead = 16 //AES-GCM认证标签长度
+
+	signContext = "GoGameServer key exchange v1"
+)
+
+var (
+	ErrKeyExchange = errors.New("frame key exchange not finished")
+	errPublicKey   = errors.New("frame public key error")
+	errSignature   = errors.New("frame key exchange signature error")
+	errSignKey     = errors.New("frame sign key must be an ed25519 private key")
+)
+
+// LoadSignKey 读取PEM(PKCS8)格式的Ed25519私钥，可通过 openssl genpkey -algorithm ed25519 生成
+func LoadSignKey(keyFile string) (ed25519.PrivateKey, error) {
+	data, err := os.ReadFile(keyFile)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errSignKey
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	signKey, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, errSignKey
+	}
+	return signKey, nil
+}
+
+// 服务器对双方公钥的签名内容
+func signMessage(clientPublicKey []byte, serverPublicKey []byte) []byte {
+	msg := make([]byte, 0, len(signContext)+len(clientPublicKey)+len(serverPublicKey))
+	msg = append(msg, signContext...)
+	msg = append(msg, clientPublicKey...)
+	msg = append(msg, serverPublicKey...)
+	return msg
+}
+
+// SignKeyExchange 服务器回复内容：服务器公钥+签名
+func (this *KeyExchange) SignKeyExchange(clientPublicKey []byte, signKey ed25519.PrivateKey) []byte {
+	signature := ed25519.Sign(signKey, signMessage(clientPublicKey, this.publicKey))
+	response := make([]byte, 0, len(this.publicKey)+len(signature))
+	response = append(response, this.publicKey...)
+	return append(response, signature...)
+}
+
+// VerifyKeyExchange 客户端校验服务器回复，通过后返回加解密对象
+func (this *KeyExchange) VerifyKeyExchange(response []byte, serverSignKey ed25519.PublicKey) (*Cipher, error) {
+	if len(response) != curve25519.PointSize+ed25519.SignatureSize {
+		return nil, errPublicKey
+	}
+	serverPublicKey := response[:curve25519.PointSize]
+	signature := response[curve25519.PointSize:]
+	if !ed25519.Verify(serverSignKey, signMessage(this.publicKey, serverPublicKey), signature) {
+		return nil, errSignature
+	}
+	return this.Cipher(serverPublicKey, false)
+}
+
+// KeyExchange X25519密钥交换
+type KeyExchange struct {
+	privateKey []byte
+	publicKey  []byte
+}
+
+func NewKeyExchange() (*KeyExchange, error) {
+	privateKey := make([]byte, curve25519.ScalarSize)
+	if _, err := io.ReadFull(rand.Reader, privateKey); err != nil {
+		return nil, err
+	}
+	publicKey, err := curve25519.X25519(privateKey, curve25519.Basepoint)
+	if err != nil {
+		return nil, err
+	}
+	return &KeyExchange{
+		privateKey: privateKey,
+		publicKey:  publicKey,
+	}, nil
+}
+
+func (this *KeyExchange) PublicKey() []byte {
+	return this.publicKey
+}
+
+// Cipher 根据对方公钥生成加解密对象
+func (this *KeyExchange) Cipher(peerPublicKey []byte, isServer bool) (*Cipher, error) {
+	if len(peerPublicKey) != curve25519.PointSize {
+		return nil, errPublicKey
+	}
+	secret, err := curve25519.X25519(this.privateKey, peerPublicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	//salt固定为客户端公钥+服务器公钥
+	var salt []byte
+	if isServer {
+		salt = append(append(salt, peerPublicKey...), this.publicKey...)
+	} else {
+		salt = append(append(salt, this.publicKey...), peerPublicKey...)
+	}
+
+	c2s, err := newAead(secret, salt, "c2s")
+	if err != nil {
+		return nil, err
+	}
+	s2c, err := newAead(secret, salt, "s2c")
+	if err != nil {
+		return nil, err
+	}
+
+	if isServer {
+		return &Cipher{sendAead: s2c, recvAead: c2s}, nil
+	}
+	return &Cipher{sendAead: c2s, recvAead: s2c}, nil
+}
+
+func newAead(secret []byte, salt []byte, info string) (cipher.AEAD, error) {
+	key := make([]byte, keyLen)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, secret, salt, []byte(info)), key); err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// Cipher AES-GCM加解密，发送和接收各自维护nonce计数器
+type Cipher struct {
+	sendAead  cipher.AEAD
+	sendNonce uint64
+	recvAead  cipher.AEAD
+	recvNonce uint64
+}
+
+func (this *Cipher) Overhead() int {
+	return this.sendAead.Overhead()
+}
+
+// Seal head为消息头，作为附加认证数据
+func (this *Cipher) Seal(data []byte, head []byte) []byte {
+	nonce := makeNonce(this.sendNonce)
+	this.sendNonce++
+	return this.sendAead.Seal(nil, nonce, data, head)
+}
+
+func (this *Cipher) Open(data []byte, head []byte) ([]byte, error) {
+	nonce := makeNonce(this.recvNonce)
+	this.recvNonce++
+	return this.recvAead.Open(nil, nonce, data, head)
+}
+
+func makeNonce(counter uint64) []byte {
+	nonce := make([]byte, cipherNonce)
+	binary.BigEndian.PutUint64(nonce[cipherNonce-8:], counter)
+	return nonce
+}
@@ -0,0 +1,285 @@
+package frame
+
+import (
+	"crypto/ed25519"
+	"encoding/binary"
+	"errors"
+)
+
+// 消息头版本
+// 客户端连接后首先发送握手包[0x00 0x00 version (compress)]，服务器回复相同格式确认
+// 未发送握手包的老客户端使用Version_Legacy
+const (
+	Version_Legacy   byte = 1 //2字节长度
+	Version_Large    byte = 2 //4字节长度
+	Version_Compress byte = 3 //4字节长度，最高位为压缩标识，握手包中附带压缩方式
+)
+
+const (
+	DefaultMaxSize = 1024 * 1024 //默认单条消息最大长度
+	legacyMaxSize  = 0xFFFF      //2字节长度能表示的最大值
+	HandshakeLen   = 3           //握手包最小长度
+	MaxHeadLen     = 4
+
+	flagCompress uint32 = 1 << 31
+)
+
+var (
+	ErrTooLarge  = errors.New("frame too large")
+	ErrVersion   = errors.New("frame version not supported")
+	ErrHandshake = errors.New("frame handshake error")
+)
+
+// Protocol 单个连接的消息头协议，握手后确定版本和压缩方式
+type Protocol struct {
+	version           byte
+	compress          byte
+	maxSize           int
+	compressThreshold int
+
+	signKey ed25519.PrivateKey
+	cipher  *Cipher
+}
+
+func NewProtocol(maxSize int, compressThreshold int) *Protocol {
+	if maxSize <= 0 {
+		maxSize = DefaultMaxSize
+	}
+	return &Protocol{
+		version:           Version_Legacy,
+		compress:          Compress_None,
+		maxSize:           maxSize,
+		compressThreshold: compressThreshold,
+	}
+}
+
+func (this *Protocol) Version() byte {
+	return this.version
+}
+
+func (this *Protocol) Compress() byte {
+	return this.compress
+}
+
+// SetVersion 设置握手后的版本和压缩方式
+func (this *Protocol) SetVersion(version byte, compress byte) {
+	this.version = version
+	this.compress = compress
+}
+
+// SetCrypto 要求密钥交换，握手包之后的第一条消息必须为客户端公钥，signKey为服务器签名私钥，nil为不加密
+func (this *Protocol) SetCrypto(signKey ed25519.PrivateKey) {
+	this.signKey = signKey
+}
+
+func (this *Protocol) requireCrypto() bool {
+	return this.signKey != nil
+}
+
+// SetCipher 设置密钥交换后的加解密对象
+func (this *Protocol) SetCipher(cipher *Cipher) {
+	this.cipher = cipher
+}
+
+// NeedKeyExchange 是否还在等待密钥交换
+func (this *Protocol) NeedKeyExchange() bool {
+	return this.requireCrypto() && this.cipher == nil
+}
+
+// AcceptKeyExchange 服务器处理客户端公钥，返回需要回复的服务器公钥和签名，之后的消息全部加密
+func (this *Protocol) AcceptKeyExchange(clientPublicKey []byte) ([]byte, error) {
+	keyExchange, err := NewKeyExchange()
+	if err != nil {
+		return nil, err
+	}
+	cipher, err := keyExchange.Cipher(clientPublicKey, true)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := this.pack(keyExchange.SignKeyExchange(clientPublicKey, this.signKey))
+	if err != nil {
+		return nil, err
+	}
+	this.cipher = cipher
+	return response, nil
+}
+
+// HeadLen 消息头长度
+func (this *Protocol) HeadLen() int {
+	if this.version == Version_Legacy {
+		return 2
+	}
+	return 4
+}
+
+// MaxSize 当前版本下允许的最大消息长度
+func (this *Protocol) MaxSize() int {
+	if this.version == Version_Legacy && this.maxSize > legacyMaxSize {
+		return legacyMaxSize
+	}
+	return this.maxSize
+}
+
+// ReadLimit 单条消息在传输中的最大长度，用于限制底层连接读取
+func (this *Protocol) ReadLimit() int {
+	return this.maxSize + MaxHeadLen + this.overhead()
+}
+
+// 加密后增加的长度
+func (this *Protocol) overhead() int {
+	if this.requireCrypto() {
+		return cipherOverhead
+	}
+	return 0
+}
+
+// ParseHead 解析消息头，返回消息体长度和是否压缩
+func (this *Protocol) ParseHead(head []byte) (int, bool, error) {
+	var msgLen int
+	var compressed bool
+	if this.version == Version_Legacy {
+		msgLen = int(binary.BigEndian.Uint16(head))
+	} else {
+		value := binary.BigEndian.Uint32(head)
+		if this.version == Version_Compress {
+			compressed = value&flagCompress != 0
+			value &^= flagCompress
+		}
+		msgLen = int(value)
+	}
+
+	if msgLen > this.MaxSize()+this.overhead() {
+		return msgLen, compressed, ErrTooLarge
+	}
+	return msgLen, compressed, nil
+}
+
+// Unpack 消息体解密、解压
+func (this *Protocol) Unpack(body []byte, compressed bool) ([]byte, error) {
+	if this.cipher != nil {
+		data, err := this.cipher.Open(body, this.packHead(len(body), compressed))
+		if err != nil {
+			return nil, err
+		}
+		body = data
+	}
+
+	if !compressed {
+		return body, nil
+	}
+	return decompress(this.compress, body, this.MaxSize())
+}
+
+// Pack 消息打包: 消息头 + 消息内容，超过压缩阈值时进行压缩，密钥交换后进行加密
+func (this *Protocol) Pack(msg []byte) ([]byte, error) {
+	if this.NeedKeyExchange() {
+		return nil, ErrKeyExchange
+	}
+	return this.pack(msg)
+}
+
+func (this *Protocol) pack(msg []byte) ([]byte, error) {
+	if len(msg) > this.MaxSize() {
+		return nil, ErrTooLarge
+	}
+
+	compressed := false
+	if this.version == Version_Compress && this.compress != Compress_None && len(msg) >= this.compressThreshold {
+		data, err := compress(this.compress, msg)
+		//压缩后没有变小则原样发送
+		if err == nil && len(data) < len(msg) {
+			msg = data
+			compressed = true
+		}
+	}
+
+	msgLen := len(msg)
+	if this.cipher != nil {
+		msgLen += this.cipher.Overhead()
+		if this.version == Version_Legacy && msgLen > legacyMaxSize {
+			return nil, ErrTooLarge
+		}
+	}
+
+	//加密时消息头作为附加认证数据，长度和压缩标识不能被修改
+	head := this.packHead(msgLen, compressed)
+	if this.cipher != nil {
+		msg = this.cipher.Seal(msg, head)
+	}
+
+	result := make([]byte, 0, len(head)+msgLen)
+	result = append(result, head...)
+	return append(result, msg...), nil
+}
+
+// 消息头
+func (this *Protocol) packHead(msgLen int, compressed bool) []byte {
+	head := make([]byte, this.HeadLen())
+	if this.version == Version_Legacy {
+		binary.BigEndian.PutUint16(head, uint16(msgLen))
+	} else {
+		value := uint32(msgLen)
+		if compressed {
+			value |= flagCompress
+		}
+		binary.BigEndian.PutUint32(head, value)
+	}
+	return head
+}
+
+// IsHandshake 是否为握手包(老客户端不会发送长度为0的消息)
+func IsHandshake(head []byte) bool {
+	return len(head) >= 2 && head[0] == 0 && head[1] == 0
+}
+
+// HandshakeExtraLen 握手包中版本号之后的附加长度
+func HandshakeExtraLen(version byte) int {
+	if version == Version_Compress {
+		return 1
+	}
+	return 0
+}
+
+// PackHandshake 握手包
+func PackHandshake(version byte, compress byte) []byte {
+	if HandshakeExtraLen(version) > 0 {
+		return []byte{0, 0, version, compress}
+	}
+	return []byte{0, 0, version}
+}
+
+// ParseHandshake 解析握手包，返回版本和压缩方式
+func ParseHandshake(data []byte) (byte, byte, error) {
+	if len(data) < HandshakeLen || !IsHandshake(data) {
+		return 0, 0, ErrHandshake
+	}
+
+	version := data[2]
+	if version != Version_Legacy && version != Version_Large && version != Version_Compress {
+		return version, 0, ErrVersion
+	}
+	if len(data) != HandshakeLen+HandshakeExtraLen(version) {
+		return version, 0, ErrHandshake
+	}
+
+	compress := Compress_None
+	if version == Version_Compress {
+		compress = data[3]
+	}
+	return version, compress, nil
+}
+
+// AcceptHandshake 服务器处理客户端握手包，返回回复内容，不支持的压缩方式降级为不压缩
+func (this *Protocol) AcceptHandshake(data []byte) ([]byte, error) {
+	version, compress, err := ParseHandshake(data)
+	if err != nil {
+		return nil, err
+	}
+	if !CheckCompress(compress) {
+		compress = Compress_None
+	}
+
+	this.SetVersion(version, compress)
+	return PackHandshake(version, compress), nil
+}
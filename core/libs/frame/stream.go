@@ -0,0 +1,175 @@
+package frame
+
+import (
+	"io"
+	"sync"
+
+	"github.com/yicaoyimuys/GoGameServer/core/libs/logger"
+	"go.uber.org/zap"
+)
+
+// StreamCodec 流式连接(socket、kcp)的消息收发，处理握手、密钥交换和消息头
+func NewStreamCodec(rw io.ReadWriteCloser, protocol *Protocol) *StreamCodec {
+	codec := &StreamCodec{
+		rw:       rw,
+		headBuf:  make([]byte, MaxHeadLen),
+		protocol: protocol,
+	}
+	return codec
+}
+
+type StreamCodec struct {
+	rw      io.ReadWriteCloser
+	headBuf []byte
+	bodyBuf []byte
+
+	protocol   *Protocol
+	handshaked bool
+	sendMutex  sync.Mutex
+}
+
+func (this *StreamCodec) Receive() ([]byte, error) {
+	//消息长度
+	if _, err := io.ReadFull(this.rw, this.headBuf[:2]); err != nil {
+		return nil, err
+	}
+
+	//握手只在第一条消息时检测
+	if !this.handshaked {
+		this.handshaked = true
+		if IsHandshake(this.headBuf) {
+			if err := this.handshake(); err != nil {
+				return nil, err
+			}
+			return this.Receive()
+		}
+	}
+
+	headLen := this.protocol.HeadLen()
+	if headLen > 2 {
+		if _, err := io.ReadFull(this.rw, this.headBuf[2:headLen]); err != nil {
+			return nil, err
+		}
+	}
+	msgLen, compressed, err := this.protocol.ParseHead(this.headBuf[:headLen])
+	if err != nil {
+		logger.Error("消息长度超出限制", zap.Int("MsgLen", msgLen), zap.Int("MaxFrameSize", this.protocol.MaxSize()))
+		return nil, err
+	}
+
+	//消息内容
+	if cap(this.bodyBuf) < msgLen {
+		this.bodyBuf = make([]byte, msgLen, msgLen+128)
+	}
+	msgBody := this.bodyBuf[:msgLen]
+	if _, err := io.ReadFull(this.rw, msgBody); err != nil {
+		return nil, err
+	}
+
+	//解密、解压
+	msgBody, err = this.protocol.Unpack(msgBody, compressed)
+	if err != nil {
+		logger.Error("消息解析失败", zap.Error(err))
+		return nil, err
+	}
+
+	//密钥交换
+	if this.protocol.NeedKeyExchange() {
+		if err := this.exchangeKey(msgBody); err != nil {
+			return nil, err
+		}
+		return this.Receive()
+	}
+
+	return msgBody, nil
+}
+
+func (this *StreamCodec) handshake() error {
+	//版本号
+	if _, err := io.ReadFull(this.rw, this.headBuf[2:HandshakeLen]); err != nil {
+		return err
+	}
+	data := append([]byte{}, this.headBuf[:HandshakeLen]...)
+
+	//附加内容
+	extraLen := HandshakeExtraLen(data[2])
+	if extraLen > 0 {
+		extra := make([]byte, extraLen)
+		if _, err := io.ReadFull(this.rw, extra); err != nil {
+			return err
+		}
+		data = append(data, extra...)
+	}
+
+	//切换版本并回复确认，之后的消息都使用新版本
+	this.sendMutex.Lock()
+	defer this.sendMutex.Unlock()
+
+	response, err := this.protocol.AcceptHandshake(data)
+	if err != nil {
+		logger.Error("握手失败", zap.Binary("Data", data), zap.Error(err))
+		return err
+	}
+	_, err = this.rw.Write(response)
+	return err
+}
+
+func (this *StreamCodec) exchangeKey(clientPublicKey []byte) error {
+	this.sendMutex.Lock()
+	defer this.sendMutex.Unlock()
+
+	response, err := this.protocol.AcceptKeyExchange(clientPublicKey)
+	if err != nil {
+		logger.Error("密钥交换失败", zap.Error(err))
+		return err
+	}
+	_, err = this.rw.Write(response)
+	return err
+}
+
+func (this *StreamCodec) Send(msg []byte) error {
+	this.sendMutex.Lock()
+	defer this.sendMutex.Unlock()
+
+	sendMsg, err := this.pack(msg)
+	if err != nil {
+		return err
+	}
+
+	_, err = this.rw.Write(sendMsg)
+	return err
+}
+
+func (this *StreamCodec) SendBatch(msgs [][]byte) error {
+	this.sendMutex.Lock()
+	defer this.sendMutex.Unlock()
+
+	//多条消息合并为一次写入，单条打包失败不影响其他消息
+	var sendBuf []byte
+	for _, msg := range msgs {
+		sendMsg, err := this.pack(msg)
+		if err != nil {
+			continue
+		}
+		sendBuf = append(sendBuf, sendMsg...)
+	}
+	if len(sendBuf) == 0 {
+		return nil
+	}
+
+	_, err := this.rw.Write(sendBuf)
+	return err
+}
+
+func (this *StreamCodec) pack(msg []byte) ([]byte, error) {
+	//密钥交换完成前返回ErrKeyExchange，由调用方处理
+	sendMsg, err := this.protocol.Pack(msg)
+	if err == ErrTooLarge {
+		logger.Error("发送消息长度超出限制", zap.Int("MsgLen", len(msg)), zap.Int("MaxFrameSize", this.protocol.MaxSize()))
+	}
+	return sendMsg, err
+}
+
+func (this *StreamCodec) Close() error {
+	return this.rw.Close()
+}
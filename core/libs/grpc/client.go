@@ -2,9 +2,9 @@ package grpc
 
 import (
 	"io"
+	"math/rand"
 	"reflect"
 	"sync"
-	"time"
 
 	"github.com/yicaoyimuys/GoGameServer/core/libs/consul"
 	"github.com/yicaoyimuys/GoGameServer/core/libs/hash"
@@ -12,7 +12,6 @@ import (
 	"github.com/yicaoyimuys/GoGameServer/core/libs/timer"
 	"go.uber.org/zap"
 
-	"github.com/spf13/cast"
 	"golang.org/x/net/context"
 	"google.golang.org/grpc"
 )
@@ -22,6 +21,9 @@ type Client struct {
 	serviceName  string
 
 	services      []string
+	weights       map[string]int
+	ring          *hash.Ring
+	loads         map[string]*consul.ServiceLoad
 	servicesMutex sync.Mutex
 
 	links     map[string]*grpc.ClientConn
@@ -43,18 +45,51 @@ func NewClient(consulClient *consul.Client, serviceName string, newPbClientFunc
 }
 
 func (this *Client) loop() {
-	timer.SetTimeOut(5*1000, this.initServices)
+	timer.DoTimer(5*1000, this.refreshServices)
 }
 
 func (this *Client) initServices() {
-	this.servicesMutex.Lock()
-	this.services = this.consulClient.GetServices(this.serviceName)
-	this.servicesMutex.Unlock()
+	this.updateServices(this.consulClient.GetServiceInfos(this.serviceName))
+	this.updateLoads()
 
 	this.initLinks()
 	this.traceServices()
 }
 
+// 定时同步服务列表，节点增减时只有一致性哈希环上相邻的Key会重新分配
+func (this *Client) refreshServices() {
+	infos := this.consulClient.GetServiceInfos(this.serviceName)
+	if len(infos) == 0 {
+		//consul异常时保留原列表
+		return
+	}
+	this.updateServices(infos)
+	this.updateLoads()
+}
+
+func (this *Client) updateLoads() {
+	loads := consul.GetServiceLoads(this.serviceName)
+
+	this.servicesMutex.Lock()
+	this.loads = loads
+	this.servicesMutex.Unlock()
+}
+
+func (this *Client) updateServices(infos []consul.ServiceInfo) {
+	services := []string{}
+	weights := make(map[string]int)
+	for _, info := range infos {
+		services = append(services, info.Addr())
+		weights[info.Addr()] = info.Weight
+	}
+
+	this.servicesMutex.Lock()
+	this.services = services
+	this.weights = weights
+	this.ring = hash.NewRing(hash.DefaultReplicas, weights)
+	this.servicesMutex.Unlock()
+}
+
 func (this *Client) initLinks() {
 	if len(this.services) == 0 {
 		timer.SetTimeOut(300, this.initServices)
@@ -86,6 +121,10 @@ func (this *Client) removeService(service string) {
 			this.services = append(this.services[:index], this.services[index+1:]...)
 		}
 	}
+	if _, ok := this.weights[service]; ok {
+		delete(this.weights, service)
+		this.ring = hash.NewRing(hash.DefaultReplicas, this.weights)
+	}
 	this.servicesMutex.Unlock()
 
 	this.traceServices()
@@ -93,20 +132,113 @@ func (this *Client) removeService(service string) {
 
 func (this *Client) GetServiceByFlag(flag string) string {
 	this.servicesMutex.Lock()
+	ring := this.ring
+	this.servicesMutex.Unlock()
+
+	if ring == nil {
+		return ""
+	}
+	return ring.Get(flag)
+}
+
+// GetServiceWeights 当前服务列表及其一致性哈希权重
+func (this *Client) GetServiceWeights() map[string]int {
+	this.servicesMutex.Lock()
+	defer this.servicesMutex.Unlock()
+
+	weights := make(map[string]int, len(this.weights))
+	for service, weight := range this.weights {
+		weights[service] = weight
+	}
+	return weights
+}
+
+// GetMovedKeys 扩缩容前预览：服务列表变为weights后，keys中会分配到其他服务的Key
+func (this *Client) GetMovedKeys(keys []string, weights map[string]int) []hash.MovedKey {
+	this.servicesMutex.Lock()
+	ring := this.ring
+	this.servicesMutex.Unlock()
+
+	if ring == nil {
+		ring = hash.NewRing(hash.DefaultReplicas, nil)
+	}
+	return ring.MovedKeys(hash.NewRing(hash.DefaultReplicas, weights), keys)
+}
+
+func (this *Client) GetServiceByRandom() string {
+	this.servicesMutex.Lock()
+	defer this.servicesMutex.Unlock()
+
+	if len(this.services) == 0 {
+		return ""
+	}
+	return this.services[rand.Intn(len(this.services))]
+}
+
+// GetServiceByLeastLoad 选择负载最低的服务，未上报负载的服务视为空闲
+func (this *Client) GetServiceByLeastLoad() string {
+	this.servicesMutex.Lock()
+	defer this.servicesMutex.Unlock()
+
 	service := ""
+	minScore := 0.0
+	for _, value := range this.services {
+		score := this.getLoadScore(value)
+		if service == "" || score < minScore {
+			service = value
+			minScore = score
+		}
+	}
+	this.addLoad(service)
+	return service
+}
+
+// GetServiceByLoad 随机选择两个服务，使用负载较低的一个(power of two choices)
+// 避免负载上报间隔内的请求都分配到同一个服务
+func (this *Client) GetServiceByLoad() string {
+	this.servicesMutex.Lock()
+	defer this.servicesMutex.Unlock()
+
 	servicesLen := len(this.services)
-	if servicesLen > 0 {
-		num := hash.GetHash([]byte(flag))
-		index := int(num % uint32(servicesLen))
-		service = this.services[index]
+	if servicesLen == 0 {
+		return ""
+	}
+	if servicesLen == 1 {
+		return this.services[0]
 	}
-	this.servicesMutex.Unlock()
 
+	index1 := rand.Intn(servicesLen)
+	index2 := rand.Intn(servicesLen - 1)
+	if index2 >= index1 {
+		index2++
+	}
+	service := this.services[index1]
+	if this.getLoadScore(this.services[index2]) < this.getLoadScore(service) {
+		service = this.services[index2]
+	}
+	this.addLoad(service)
 	return service
 }
 
-func (this *Client) GetServiceByRandom() string {
-	return this.GetServiceByFlag(cast.ToString(time.Now().Unix()))
+func (this *Client) getLoadScore(service string) float64 {
+	load, ok := this.loads[service]
+	if !ok {
+		return 0
+	}
+	return load.Score(this.weights[service])
+}
+
+// 两次负载上报之间，按已分配的数量估算负载
+func (this *Client) addLoad(service string) {
+	if service == "" {
+		return
+	}
+	load, ok := this.loads[service]
+	if !ok {
+		load = &consul.ServiceLoad{}
+		this.loads[service] = load
+	}
+	load.Sessions++
 }
 
 func (this *Client) getLink(service string) *grpc.ClientConn {
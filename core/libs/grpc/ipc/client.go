@@ -6,6 +6,7 @@ import (
 
 	"github.com/yicaoyimuys/GoGameServer/core/libs/consul"
 	myGprc "github.com/yicaoyimuys/GoGameServer/core/libs/grpc"
+	"github.com/yicaoyimuys/GoGameServer/core/libs/hash"
 	"github.com/yicaoyimuys/GoGameServer/core/libs/stack"
 
 	"google.golang.org/grpc"
@@ -16,10 +17,23 @@ type ClientRecvHandle func(stream Ipc_TransferClient, msg *Res)
 type Client struct {
 	grpcClient        *myGprc.Client
 	recvHandle        ClientRecvHandle
-	serverStreams     map[string]Ipc_TransferClient
+	serverStreams     map[string]*clientStream
 	serverStreamMutex sync.Mutex
 }
 
+// grpc的stream不能同时在多个协程中发送，发送顺序即服务器接收顺序
+type clientStream struct {
+	Ipc_TransferClient
+	sendMutex sync.Mutex
+}
+
+func (this *clientStream) send(req *Req) error {
+	this.sendMutex.Lock()
+	defer this.sendMutex.Unlock()
+
+	return this.Send(req)
+}
+
 func NewClient(consulClient *consul.Client, serviceName string, handle ClientRecvHandle) *Client {
 	grpcClient := myGprc.NewClient(consulClient, serviceName, func(conn *grpc.ClientConn) interface{} {
 		return NewIpcClient(conn)
@@ -28,7 +42,7 @@ func NewClient(consulClient *consul.Client, serviceName string, handle ClientRec
 	client := &Client{
 		grpcClient:    grpcClient,
 		recvHandle:    handle,
-		serverStreams: make(map[string]Ipc_TransferClient),
+		serverStreams: make(map[string]*clientStream),
 	}
 	return client
 }
@@ -48,11 +62,12 @@ func (this *Client) loop(service string, stream Ipc_TransferClient) {
 		if err != nil {
 			return
 		}
-		go this.dealRecvHandle(stream, in)
+		//按接收顺序处理，控制消息(分组、绑定用户等)与之后的消息顺序不变
+		this.dealRecvHandle(stream, in)
 	}
 }
 
-func (this *Client) getStream(service string) Ipc_TransferClient {
+func (this *Client) getStream(service string) *clientStream {
 	this.serverStreamMutex.Lock()
 	defer this.serverStreamMutex.Unlock()
 
@@ -67,9 +82,9 @@ func (this *Client) getStream(service string) Ipc_TransferClient {
 	if transferClient == nil {
 		return nil
 	}
-	stream = transferClient.(Ipc_TransferClient)
+	stream = &clientStream{Ipc_TransferClient: transferClient.(Ipc_TransferClient)}
 	this.serverStreams[service] = stream
-	go this.loop(service, stream)
+	go this.loop(service, stream.Ipc_TransferClient)
 
 	return stream
 }
@@ -78,7 +93,9 @@ func (this *Client) removeStream(service string) {
 	this.serverStreamMutex.Lock()
 	if stream, ok := this.serverStreams[service]; ok {
 		stream.Context().Done()
+		stream.sendMutex.Lock()
 		stream.CloseSend()
+		stream.sendMutex.Unlock()
 		delete(this.serverStreams, service)
 	}
 	this.serverStreamMutex.Unlock()
@@ -92,7 +109,17 @@ func (this *Client) GetServiceByFlag(flag string) string {
 	return this.grpcClient.GetServiceByFlag(flag)
 }
 
-func (this *Client) Send(senderServiceIdentify string, userSessionId uint64, data []byte, receiverService string) error {
+func (this *Client) Send(senderServiceIdentify string, userSessionId uint64, remoteAddr string, data []byte, receiverService string) error {
+	return this.SendReq(&Req{
+		ServiceIdentify: senderServiceIdentify,
+		UserSessionId:   userSessionId,
+		RemoteAddr:      remoteAddr,
+		Data:            data,
+	}, receiverService)
+}
+
+// SendReq 需设置RequestSeq等其他字段时使用
+func (this *Client) SendReq(req *Req, receiverService string) error {
 	if receiverService == "" {
 		return errors.New("service is null")
 	}
@@ -102,9 +129,24 @@ func (this *Client) Send(senderServiceIdentify string, userSessionId uint64, dat
 		return errors.New("stream is null")
 	}
 
-	return stream.Send(&Req{
-		ServiceIdentify: senderServiceIdentify,
-		UserSessionId:   userSessionId,
-		Data:            data,
-	})
+	return stream.send(req)
+}
+
+// GetServiceWeights 当前服务列表及其一致性哈希权重
+func (this *Client) GetServiceWeights() map[string]int {
+	return this.grpcClient.GetServiceWeights()
+}
+
+// GetMovedKeys 扩缩容前预览：服务列表变为weights后，keys中会分配到其他服务的Key
+func (this *Client) GetMovedKeys(keys []string, weights map[string]int) []hash.MovedKey {
+	return this.grpcClient.GetMovedKeys(keys, weights)
+}
+
+// GetServiceByLoad 按负载分配服务器，用于不需要固定服务器的消息
+func (this *Client) GetServiceByLoad() string {
+	return this.grpcClient.GetServiceByLoad()
+}
+
+func (this *Client) GetServiceByLeastLoad() string {
+	return this.grpcClient.GetServiceByLeastLoad()
 }
@@ -20,6 +20,80 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+//connector需处理的控制消息
+type ControlType int32
+
+const (
+	ControlType_CONTROL_NONE          ControlType = 0
+	ControlType_CONTROL_GROUP_JOIN    ControlType = 1  //userSessionIds加入group
+	ControlType_CONTROL_GROUP_LEAVE   ControlType = 2  //userSessionIds离开group
+	ControlType_CONTROL_GROUP_PUBLISH ControlType = 3  //data发送给group中的所有Session
+	ControlType_CONTROL_KICK          ControlType = 4  //发送data后断开userSessionIds的连接，reason为原因
+	ControlType_CONTROL_BIND_USER     ControlType = 5  //userSessionIds绑定用户userId
+	ControlType_CONTROL_SET_ATTRS     ControlType = 6  //设置Session属性attrs，值为空时删除该属性
+	ControlType_CONTROL_CLEAR_ATTRS   ControlType = 7  //清除Session所有属性
+	ControlType_CONTROL_PIN           ControlType = 8  //之后发送给serviceName的消息都发送到service
+	ControlType_CONTROL_FORWARD       ControlType = 9  //代替客户端将data发送给serviceName，service为空时由connector分配
+	ControlType_CONTROL_MOVED         ControlType = 10 //userSessionIds已断线重连到其他connector，之前的消息都已发送
+)
+
+// Enum value maps for ControlType.
+var (
+	ControlType_name = map[int32]string{
+		0:  "CONTROL_NONE",
+		1:  "CONTROL_GROUP_JOIN",
+		2:  "CONTROL_GROUP_LEAVE",
+		3:  "CONTROL_GROUP_PUBLISH",
+		4:  "CONTROL_KICK",
+		5:  "CONTROL_BIND_USER",
+		6:  "CONTROL_SET_ATTRS",
+		7:  "CONTROL_CLEAR_ATTRS",
+		8:  "CONTROL_PIN",
+		9:  "CONTROL_FORWARD",
+		10: "CONTROL_MOVED",
+	}
+	ControlType_value = map[string]int32{
+		"CONTROL_NONE":          0,
+		"CONTROL_GROUP_JOIN":    1,
+		"CONTROL_GROUP_LEAVE":   2,
+		"CONTROL_GROUP_PUBLISH": 3,
+		"CONTROL_KICK":          4,
+		"CONTROL_BIND_USER":     5,
+		"CONTROL_SET_ATTRS":     6,
+		"CONTROL_CLEAR_ATTRS":   7,
+		"CONTROL_PIN":           8,
+		"CONTROL_FORWARD":       9,
+		"CONTROL_MOVED":         10,
+	}
+)
+
+func (x ControlType) Enum() *ControlType {
+	p := new(ControlType)
+	*p = x
+	return p
+}
+
+func (x ControlType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ControlType) Descriptor() protoreflect.EnumDescriptor {
+	return file_ipc_proto_enumTypes[0].Descriptor()
+}
+
+func (ControlType) Type() protoreflect.EnumType {
+	return &file_ipc_proto_enumTypes[0]
+}
+
+func (x ControlType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ControlType.Descriptor instead.
+func (ControlType) EnumDescriptor() ([]byte, []int) {
+	return file_ipc_proto_rawDescGZIP(), []int{0}
+}
+
 type Req struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -28,6 +102,10 @@ type Req struct {
 	ServiceIdentify string `protobuf:"bytes,1,opt,name=serviceIdentify,proto3" json:"serviceIdentify,omitempty"`
 	UserSessionId   uint64 `protobuf:"varint,2,opt,name=userSessionId,proto3" json:"userSessionId,omitempty"`
 	Data            []byte `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`
+	RemoteAddr      string `protobuf:"bytes,4,opt,name=remoteAddr,proto3" json:"remoteAddr,omitempty"`
+	RequestSeq      uint32 `protobuf:"varint,5,opt,name=requestSeq,proto3" json:"requestSeq,omitempty"`
+	Closed          bool   `protobuf:"varint,6,opt,name=closed,proto3" json:"closed,omitempty"` //客户端已断开，处理完之前的消息后关闭BackSession
+	UserId          uint64 `protobuf:"varint,7,opt,name=userId,proto3" json:"userId,omitempty"` //connector中Session已绑定的用户ID，0为未登录
 }
 
 func (x *Req) Reset() {
@@ -83,13 +161,50 @@ func (x *Req) GetData() []byte {
 	return nil
 }
 
+func (x *Req) GetRemoteAddr() string {
+	if x != nil {
+		return x.RemoteAddr
+	}
+	return ""
+}
+
+func (x *Req) GetRequestSeq() uint32 {
+	if x != nil {
+		return x.RequestSeq
+	}
+	return 0
+}
+
+func (x *Req) GetClosed() bool {
+	if x != nil {
+		return x.Closed
+	}
+	return false
+}
+
+func (x *Req) GetUserId() uint64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
 type Res struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	UserSessionIds []uint64 `protobuf:"varint,1,rep,packed,name=userSessionIds,proto3" json:"userSessionIds,omitempty"`
-	Data           []byte   `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
+	UserSessionIds []uint64          `protobuf:"varint,1,rep,packed,name=userSessionIds,proto3" json:"userSessionIds,omitempty"`
+	Data           []byte            `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
+	Reliable       bool              `protobuf:"varint,3,opt,name=reliable,proto3" json:"reliable,omitempty"`
+	RequestSeq     uint32            `protobuf:"varint,4,opt,name=requestSeq,proto3" json:"requestSeq,omitempty"`
+	Control        ControlType       `protobuf:"varint,5,opt,name=control,proto3,enum=ControlType" json:"control,omitempty"`
+	Group          string            `protobuf:"bytes,6,opt,name=group,proto3" json:"group,omitempty"`
+	Reason         string            `protobuf:"bytes,7,opt,name=reason,proto3" json:"reason,omitempty"`
+	UserId         uint64            `protobuf:"varint,8,opt,name=userId,proto3" json:"userId,omitempty"`
+	Attrs          map[string]string `protobuf:"bytes,9,rep,name=attrs,proto3" json:"attrs,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	ServiceName    string            `protobuf:"bytes,10,opt,name=serviceName,proto3" json:"serviceName,omitempty"`
+	Service        string            `protobuf:"bytes,11,opt,name=service,proto3" json:"service,omitempty"`
 }
 
 func (x *Res) Reset() {
@@ -138,24 +253,131 @@ func (x *Res) GetData() []byte {
 	return nil
 }
 
+func (x *Res) GetReliable() bool {
+	if x != nil {
+		return x.Reliable
+	}
+	return false
+}
+
+func (x *Res) GetRequestSeq() uint32 {
+	if x != nil {
+		return x.RequestSeq
+	}
+	return 0
+}
+
+func (x *Res) GetControl() ControlType {
+	if x != nil {
+		return x.Control
+	}
+	return ControlType_CONTROL_NONE
+}
+
+func (x *Res) GetGroup() string {
+	if x != nil {
+		return x.Group
+	}
+	return ""
+}
+
+func (x *Res) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *Res) GetUserId() uint64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *Res) GetAttrs() map[string]string {
+	if x != nil {
+		return x.Attrs
+	}
+	return nil
+}
+
+func (x *Res) GetServiceName() string {
+	if x != nil {
+		return x.ServiceName
+	}
+	return ""
+}
+
+func (x *Res) GetService() string {
+	if x != nil {
+		return x.Service
+	}
+	return ""
+}
+
 var File_ipc_proto protoreflect.FileDescriptor
 
 var file_ipc_proto_rawDesc = []byte{
-	0x0a, 0x09, 0x69, 0x70, 0x63, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x69, 0x0a, 0x03, 0x52,
-	0x65, 0x71, 0x12, 0x28, 0x0a, 0x0f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x49, 0x64, 0x65,
-	0x6e, 0x74, 0x69, 0x66, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x73, 0x65, 0x72,
-	0x76, 0x69, 0x63, 0x65, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x79, 0x12, 0x24, 0x0a, 0x0d,
-	0x75, 0x73, 0x65, 0x72, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x18, 0x02, 0x20,
-	0x01, 0x28, 0x04, 0x52, 0x0d, 0x75, 0x73, 0x65, 0x72, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e,
-	0x49, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c,
-	0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x22, 0x41, 0x0a, 0x03, 0x52, 0x65, 0x73, 0x12, 0x26, 0x0a,
-	0x0e, 0x75, 0x73, 0x65, 0x72, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x73, 0x18,
-	0x01, 0x20, 0x03, 0x28, 0x04, 0x52, 0x0e, 0x75, 0x73, 0x65, 0x72, 0x53, 0x65, 0x73, 0x73, 0x69,
-	0x6f, 0x6e, 0x49, 0x64, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x02, 0x20,
-	0x01, 0x28, 0x0c, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x32, 0x23, 0x0a, 0x03, 0x49, 0x70, 0x63,
-	0x12, 0x1c, 0x0a, 0x08, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x12, 0x04, 0x2e, 0x52,
-	0x65, 0x71, 0x1a, 0x04, 0x2e, 0x52, 0x65, 0x73, 0x22, 0x00, 0x28, 0x01, 0x30, 0x01, 0x42, 0x07,
-	0x5a, 0x05, 0x2e, 0x3b, 0x69, 0x70, 0x63, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x0a, 0x09, 0x69, 0x70, 0x63, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xd9, 0x01, 0x0a, 0x03,
+	0x52, 0x65, 0x71, 0x12, 0x28, 0x0a, 0x0f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x49, 0x64,
+	0x65, 0x6e, 0x74, 0x69, 0x66, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x73, 0x65,
+	0x72, 0x76, 0x69, 0x63, 0x65, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x79, 0x12, 0x24, 0x0a,
+	0x0d, 0x75, 0x73, 0x65, 0x72, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x0d, 0x75, 0x73, 0x65, 0x72, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f,
+	0x6e, 0x49, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x12, 0x1e, 0x0a, 0x0a, 0x72, 0x65, 0x6d, 0x6f, 0x74,
+	0x65, 0x41, 0x64, 0x64, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x72, 0x65, 0x6d,
+	0x6f, 0x74, 0x65, 0x41, 0x64, 0x64, 0x72, 0x12, 0x1e, 0x0a, 0x0a, 0x72, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x53, 0x65, 0x71, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0a, 0x72, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x53, 0x65, 0x71, 0x12, 0x16, 0x0a, 0x06, 0x63, 0x6c, 0x6f, 0x73, 0x65,
+	0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x63, 0x6c, 0x6f, 0x73, 0x65, 0x64, 0x12,
+	0x16, 0x0a, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x64, 0x18, 0x07, 0x20, 0x01, 0x28, 0x04, 0x52,
+	0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x64, 0x22, 0x88, 0x03, 0x0a, 0x03, 0x52, 0x65, 0x73, 0x12,
+	0x26, 0x0a, 0x0e, 0x75, 0x73, 0x65, 0x72, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x49, 0x64,
+	0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x04, 0x52, 0x0e, 0x75, 0x73, 0x65, 0x72, 0x53, 0x65, 0x73,
+	0x73, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x12, 0x1a, 0x0a, 0x08, 0x72,
+	0x65, 0x6c, 0x69, 0x61, 0x62, 0x6c, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x72,
+	0x65, 0x6c, 0x69, 0x61, 0x62, 0x6c, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x72, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x53, 0x65, 0x71, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0a, 0x72, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x53, 0x65, 0x71, 0x12, 0x26, 0x0a, 0x07, 0x63, 0x6f, 0x6e, 0x74, 0x72,
+	0x6f, 0x6c, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0c, 0x2e, 0x43, 0x6f, 0x6e, 0x74, 0x72,
+	0x6f, 0x6c, 0x54, 0x79, 0x70, 0x65, 0x52, 0x07, 0x63, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x12,
+	0x14, 0x0a, 0x05, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
+	0x67, 0x72, 0x6f, 0x75, 0x70, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18,
+	0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x12, 0x16, 0x0a,
+	0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x64, 0x18, 0x08, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x75,
+	0x73, 0x65, 0x72, 0x49, 0x64, 0x12, 0x25, 0x0a, 0x05, 0x61, 0x74, 0x74, 0x72, 0x73, 0x18, 0x09,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x52, 0x65, 0x73, 0x2e, 0x41, 0x74, 0x74, 0x72, 0x73,
+	0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x05, 0x61, 0x74, 0x74, 0x72, 0x73, 0x12, 0x20, 0x0a, 0x0b,
+	0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x0a, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0b, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x18,
+	0x0a, 0x07, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x07, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x1a, 0x38, 0x0a, 0x0a, 0x41, 0x74, 0x74, 0x72,
+	0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02,
+	0x38, 0x01, 0x2a, 0xfd, 0x01, 0x0a, 0x0b, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x54, 0x79,
+	0x70, 0x65, 0x12, 0x10, 0x0a, 0x0c, 0x43, 0x4f, 0x4e, 0x54, 0x52, 0x4f, 0x4c, 0x5f, 0x4e, 0x4f,
+	0x4e, 0x45, 0x10, 0x00, 0x12, 0x16, 0x0a, 0x12, 0x43, 0x4f, 0x4e, 0x54, 0x52, 0x4f, 0x4c, 0x5f,
+	0x47, 0x52, 0x4f, 0x55, 0x50, 0x5f, 0x4a, 0x4f, 0x49, 0x4e, 0x10, 0x01, 0x12, 0x17, 0x0a, 0x13,
+	0x43, 0x4f, 0x4e, 0x54, 0x52, 0x4f, 0x4c, 0x5f, 0x47, 0x52, 0x4f, 0x55, 0x50, 0x5f, 0x4c, 0x45,
+	0x41, 0x56, 0x45, 0x10, 0x02, 0x12, 0x19, 0x0a, 0x15, 0x43, 0x4f, 0x4e, 0x54, 0x52, 0x4f, 0x4c,
+	0x5f, 0x47, 0x52, 0x4f, 0x55, 0x50, 0x5f, 0x50, 0x55, 0x42, 0x4c, 0x49, 0x53, 0x48, 0x10, 0x03,
+	0x12, 0x10, 0x0a, 0x0c, 0x43, 0x4f, 0x4e, 0x54, 0x52, 0x4f, 0x4c, 0x5f, 0x4b, 0x49, 0x43, 0x4b,
+	0x10, 0x04, 0x12, 0x15, 0x0a, 0x11, 0x43, 0x4f, 0x4e, 0x54, 0x52, 0x4f, 0x4c, 0x5f, 0x42, 0x49,
+	0x4e, 0x44, 0x5f, 0x55, 0x53, 0x45, 0x52, 0x10, 0x05, 0x12, 0x15, 0x0a, 0x11, 0x43, 0x4f, 0x4e,
+	0x54, 0x52, 0x4f, 0x4c, 0x5f, 0x53, 0x45, 0x54, 0x5f, 0x41, 0x54, 0x54, 0x52, 0x53, 0x10, 0x06,
+	0x12, 0x17, 0x0a, 0x13, 0x43, 0x4f, 0x4e, 0x54, 0x52, 0x4f, 0x4c, 0x5f, 0x43, 0x4c, 0x45, 0x41,
+	0x52, 0x5f, 0x41, 0x54, 0x54, 0x52, 0x53, 0x10, 0x07, 0x12, 0x0f, 0x0a, 0x0b, 0x43, 0x4f, 0x4e,
+	0x54, 0x52, 0x4f, 0x4c, 0x5f, 0x50, 0x49, 0x4e, 0x10, 0x08, 0x12, 0x13, 0x0a, 0x0f, 0x43, 0x4f,
+	0x4e, 0x54, 0x52, 0x4f, 0x4c, 0x5f, 0x46, 0x4f, 0x52, 0x57, 0x41, 0x52, 0x44, 0x10, 0x09, 0x12,
+	0x11, 0x0a, 0x0d, 0x43, 0x4f, 0x4e, 0x54, 0x52, 0x4f, 0x4c, 0x5f, 0x4d, 0x4f, 0x56, 0x45, 0x44,
+	0x10, 0x0a, 0x32, 0x23, 0x0a, 0x03, 0x49, 0x70, 0x63, 0x12, 0x1c, 0x0a, 0x08, 0x54, 0x72, 0x61,
+	0x6e, 0x73, 0x66, 0x65, 0x72, 0x12, 0x04, 0x2e, 0x52, 0x65, 0x71, 0x1a, 0x04, 0x2e, 0x52, 0x65,
+	0x73, 0x22, 0x00, 0x28, 0x01, 0x30, 0x01, 0x42, 0x07, 0x5a, 0x05, 0x2e, 0x3b, 0x69, 0x70, 0x63,
+	0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -170,19 +392,24 @@ func file_ipc_proto_rawDescGZIP() []byte {
 	return file_ipc_proto_rawDescData
 }
 
-var file_ipc_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_ipc_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_ipc_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
 var file_ipc_proto_goTypes = []interface{}{
-	(*Req)(nil), // 0: Req
-	(*Res)(nil), // 1: Res
+	(ControlType)(0), // 0: ControlType
+	(*Req)(nil),      // 1: Req
+	(*Res)(nil),      // 2: Res
+	nil,              // 3: Res.AttrsEntry
 }
 var file_ipc_proto_depIdxs = []int32{
-	0, // 0: Ipc.Transfer:input_type -> Req
-	1, // 1: Ipc.Transfer:output_type -> Res
-	1, // [1:2] is the sub-list for method output_type
-	0, // [0:1] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	0, // 0: Res.control:type_name -> ControlType
+	3, // 1: Res.attrs:type_name -> Res.AttrsEntry
+	1, // 2: Ipc.Transfer:input_type -> Req
+	2, // 3: Ipc.Transfer:output_type -> Res
+	3, // [3:4] is the sub-list for method output_type
+	2, // [2:3] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
 }
 
 func init() { file_ipc_proto_init() }
@@ -221,13 +448,14 @@ func file_ipc_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_ipc_proto_rawDesc,
-			NumEnums:      0,
-			NumMessages:   2,
+			NumEnums:      1,
+			NumMessages:   3,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_ipc_proto_goTypes,
 		DependencyIndexes: file_ipc_proto_depIdxs,
+		EnumInfos:         file_ipc_proto_enumTypes,
 		MessageInfos:      file_ipc_proto_msgTypes,
 	}.Build()
 	File_ipc_proto = out.File
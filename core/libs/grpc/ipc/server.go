@@ -11,6 +11,13 @@ import (
 	"google.golang.org/grpc"
 )
 
+const (
+	//每个Stream处理消息的协程数，同一Session的消息由同一协程按顺序处理
+	streamWorkerNum = 16
+	//每个协程等待处理的消息数，超出时阻塞接收，协程只分发到Session的接收队列，不会阻塞
+	streamWorkerQueueSize = 1024
+)
+
 type ServerRecvHandle func(stream *Stream, msg *Req)
 type StreamSession interface {
 	Close()
@@ -18,17 +25,82 @@ type StreamSession interface {
 
 type Stream struct {
 	transferServer Ipc_TransferServer
+	sendMutex      sync.Mutex
 	sessions       []StreamSession
 	sessionsMutex  sync.Mutex
 	closeFlag      int32
 }
 
+// grpc的stream不能同时在多个协程中发送
+func (this *Stream) send(msg *Res) error {
+	this.sendMutex.Lock()
+	defer this.sendMutex.Unlock()
+
+	return this.transferServer.Send(msg)
+}
+
 func (this *Stream) Send(userSessionIds []uint64, data []byte) error {
 	msg := &Res{
 		UserSessionIds: userSessionIds,
 		Data:           data,
 	}
-	return this.transferServer.Send(msg)
+	return this.send(msg)
+}
+
+// SendReliable 客户端需确认收到，connector负责重发
+func (this *Stream) SendReliable(userSessionIds []uint64, data []byte) error {
+	msg := &Res{
+		UserSessionIds: userSessionIds,
+		Data:           data,
+		Reliable:       true,
+	}
+	return this.send(msg)
+}
+
+// SendResponse 对带序号请求的回复，connector会带上序号发送给客户端
+func (this *Stream) SendResponse(userSessionId uint64, data []byte, requestSeq uint32) error {
+	msg := &Res{
+		UserSessionIds: []uint64{userSessionId},
+		Data:           data,
+		RequestSeq:     requestSeq,
+	}
+	return this.send(msg)
+}
+
+// JoinGroup Session加入connector中的分组
+func (this *Stream) JoinGroup(group string, userSessionIds []uint64) error {
+	msg := &Res{
+		UserSessionIds: userSessionIds,
+		Control:        ControlType_CONTROL_GROUP_JOIN,
+		Group:          group,
+	}
+	return this.send(msg)
+}
+
+// LeaveGroup Session离开connector中的分组
+func (this *Stream) LeaveGroup(group string, userSessionIds []uint64) error {
+	msg := &Res{
+		UserSessionIds: userSessionIds,
+		Control:        ControlType_CONTROL_GROUP_LEAVE,
+		Group:          group,
+	}
+	return this.send(msg)
+}
+
+// PublishGroup 由connector发送给分组中的所有Session
+func (this *Stream) PublishGroup(group string, data []byte) error {
+	msg := &Res{
+		Data:    data,
+		Control: ControlType_CONTROL_GROUP_PUBLISH,
+		Group:   group,
+	}
+	return this.send(msg)
+}
+
+// SendControl 发送kick、绑定用户等控制消息，由connector处理
+func (this *Stream) SendControl(userSessionIds []uint64, msg *Res) error {
+	msg.UserSessionIds = userSessionIds
+	return this.send(msg)
 }
 
 func (this *Stream) IsClosed() bool {
@@ -114,6 +186,16 @@ func (this *Server) SendToAllClient(userSessionIds []uint64, data []byte) {
 	}
 }
 
+// PublishToGroup 每个connector只发送一次，由connector发送给分组中的所有Session
+func (this *Server) PublishToGroup(group string, data []byte) {
+	this.streamMutex.Lock()
+	defer this.streamMutex.Unlock()
+
+	for _, stream := range this.streams {
+		stream.PublishGroup(group, data)
+	}
+}
+
 func (this *Server) mustEmbedUnimplementedIpcServer() {
 
 }
@@ -121,11 +203,23 @@ func (this *Server) mustEmbedUnimplementedIpcServer() {
 func (this *Server) Transfer(stream Ipc_TransferServer) error {
 	defer stack.TryError()
 
-	s := &Stream{stream, []StreamSession{}, sync.Mutex{}, 0}
+	s := &Stream{transferServer: stream, sessions: []StreamSession{}}
 	this.addStream(s)
 
 	defer this.removeStream(s)
 
+	//按Session分配到固定的协程，保证同一Session的消息顺序
+	workers := make([]chan *Req, streamWorkerNum)
+	for i := range workers {
+		workers[i] = make(chan *Req, streamWorkerQueueSize)
+		go this.work(s, workers[i])
+	}
+	defer func() {
+		for _, worker := range workers {
+			close(worker)
+		}
+	}()
+
 	for {
 		in, err := s.transferServer.Recv()
 		if err == io.EOF {
@@ -135,7 +229,13 @@ func (this *Server) Transfer(stream Ipc_TransferServer) error {
 			return err
 		}
 
-		go this.dealServerRecvHandle(s, in)
+		workers[in.UserSessionId%streamWorkerNum] <- in
+	}
+}
+
+func (this *Server) work(stream *Stream, worker chan *Req) {
+	for msg := range worker {
+		this.dealServerRecvHandle(stream, msg)
 	}
 }
 
@@ -0,0 +1,109 @@
+package hash
+
+import (
+	"sort"
+	"strconv"
+)
+
+// DefaultReplicas 权重为1的节点在环上的虚拟节点数量
+const DefaultReplicas = 160
+
+// Ring 一致性哈希环，创建后只读，节点变化时重新创建
+type Ring struct {
+	replicas int
+	weights  map[string]int
+	hashes   []uint32
+	nodes    map[uint32]string
+}
+
+// MovedKey 节点变化后分配到其他节点的Key
+type MovedKey struct {
+	Key  string
+	From string
+	To   string
+}
+
+// NewRing weights为节点及其权重，权重小于1时按1处理，replicas小于1时使用DefaultReplicas
+func NewRing(replicas int, weights map[string]int) *Ring {
+	if replicas < 1 {
+		replicas = DefaultReplicas
+	}
+
+	ring := &Ring{
+		replicas: replicas,
+		weights:  make(map[string]int, len(weights)),
+		nodes:    make(map[uint32]string),
+	}
+
+	//按节点名排序，哈希冲突时结果与节点顺序无关
+	names := make([]string, 0, len(weights))
+	for node := range weights {
+		names = append(names, node)
+	}
+	sort.Strings(names)
+
+	for _, node := range names {
+		weight := weights[node]
+		if weight < 1 {
+			weight = 1
+		}
+		ring.weights[node] = weight
+
+		for i := 0; i < replicas*weight; i++ {
+			h := GetHash([]byte(node + "#" + strconv.Itoa(i)))
+			if _, exists := ring.nodes[h]; exists {
+				continue
+			}
+			ring.nodes[h] = node
+			ring.hashes = append(ring.hashes, h)
+		}
+	}
+	sort.Slice(ring.hashes, func(i, j int) bool {
+		return ring.hashes[i] < ring.hashes[j]
+	})
+
+	return ring
+}
+
+// Get 获取Key所属的节点，环为空时返回空字符串
+func (this *Ring) Get(key string) string {
+	if len(this.hashes) == 0 {
+		return ""
+	}
+
+	h := GetHash([]byte(key))
+	index := sort.Search(len(this.hashes), func(i int) bool {
+		return this.hashes[i] >= h
+	})
+	if index == len(this.hashes) {
+		index = 0
+	}
+	return this.nodes[this.hashes[index]]
+}
+
+// Len 节点数量
+func (this *Ring) Len() int {
+	return len(this.weights)
+}
+
+// Weights 节点及其权重
+func (this *Ring) Weights() map[string]int {
+	weights := make(map[string]int, len(this.weights))
+	for node, weight := range this.weights {
+		weights[node] = weight
+	}
+	return weights
+}
+
+// MovedKeys 列出从当前环切换到newRing后分配到其他节点的Key
+func (this *Ring) MovedKeys(newRing *Ring, keys []string) []MovedKey {
+	moved := []MovedKey{}
+	for _, key := range keys {
+		from := this.Get(key)
+		to := newRing.Get(key)
+		if from != to {
+			moved = append(moved, MovedKey{Key: key, From: from, To: to})
+		}
+	}
+	return moved
+}
@@ -0,0 +1,102 @@
+package hash
+
+import (
+	"math"
+	"strconv"
+	"testing"
+)
+
+func ringKeys(n int) []string {
+	keys := make([]string, n)
+	for i := range keys {
+		keys[i] = "user." + strconv.Itoa(i)
+	}
+	return keys
+}
+
+func TestRingAddNode(t *testing.T) {
+	keys := ringKeys(20000)
+	for _, n := range []int{2, 4, 8} {
+		weights := map[string]int{}
+		for i := 0; i < n; i++ {
+			weights["node"+strconv.Itoa(i)] = 1
+		}
+		oldRing := NewRing(0, weights)
+		weights["new"] = 1
+		newRing := NewRing(0, weights)
+
+		//只有分配到新节点的Key移动，约为1/(n+1)
+		moved := oldRing.MovedKeys(newRing, keys)
+		for _, v := range moved {
+			if v.To != "new" {
+				t.Fatalf("n=%d: key %s moved from %s to %s", n, v.Key, v.From, v.To)
+			}
+		}
+		ratio := float64(len(moved)) / float64(len(keys))
+		want := 1 / float64(n+1)
+		if math.Abs(ratio-want) > want*0.25 {
+			t.Fatalf("n=%d: moved ratio = %.3f, want about %.3f", n, ratio, want)
+		}
+	}
+}
+
+func TestRingRemoveNode(t *testing.T) {
+	keys := ringKeys(20000)
+	weights := map[string]int{"node0": 1, "node1": 1, "node2": 1, "node3": 1}
+	oldRing := NewRing(0, weights)
+	delete(weights, "node2")
+	newRing := NewRing(0, weights)
+
+	//只有原来在删除节点上的Key移动
+	moved := oldRing.MovedKeys(newRing, keys)
+	for _, v := range moved {
+		if v.From != "node2" {
+			t.Fatalf("key %s moved from %s to %s", v.Key, v.From, v.To)
+		}
+	}
+	if len(moved) == 0 {
+		t.Fatal("no key moved")
+	}
+}
+
+func TestRingWeight(t *testing.T) {
+	keys := ringKeys(30000)
+	weights := map[string]int{"node1": 1, "node2": 2, "node3": 3}
+	ring := NewRing(0, weights)
+
+	counts := map[string]int{}
+	for _, key := range keys {
+		counts[ring.Get(key)]++
+	}
+
+	//Key数量与权重成比例
+	totalWeight := 6
+	for node, weight := range weights {
+		ratio := float64(counts[node]) / float64(len(keys))
+		want := float64(weight) / float64(totalWeight)
+		if math.Abs(ratio-want) > want*0.25 {
+			t.Fatalf("%s: ratio = %.3f, want about %.3f", node, ratio, want)
+		}
+	}
+	if counts["node3"] <= counts["node2"] || counts["node2"] <= counts["node1"] {
+		t.Fatalf("counts not skewed by weight: %v", counts)
+	}
+}
+
+func TestRingGet(t *testing.T) {
+	if node := NewRing(0, nil).Get("user.1"); node != "" {
+		t.Fatalf("empty ring Get = %s", node)
+	}
+
+	//权重小于1时按1处理，相同节点得到相同结果
+	ring1 := NewRing(0, map[string]int{"node1": 0, "node2": 1, "node3": 1})
+	ring2 := NewRing(0, map[string]int{"node3": 1, "node2": 1, "node1": 1})
+	if ring1.Weights()["node1"] != 1 || ring1.Len() != 3 {
+		t.Fatalf("weights = %v", ring1.Weights())
+	}
+	for _, key := range ringKeys(1000) {
+		if ring1.Get(key) != ring2.Get(key) {
+			t.Fatalf("key %s: %s != %s", key, ring1.Get(key), ring2.Get(key))
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package iplist
+
+import (
+	"net"
+	"strings"
+)
+
+// IpList IP及CIDR网段列表，例如: 1.2.3.4、10.0.0.0/8
+type IpList struct {
+	nets []*net.IPNet
+}
+
+// NewIpList 格式错误的项会被忽略并通过invalid返回
+func NewIpList(items []string) (list *IpList, invalid []string) {
+	list = &IpList{}
+	for _, item := range items {
+		item = strings.TrimSpace(item)
+		if len(item) == 0 {
+			continue
+		}
+		ipNet := ParseIpNet(item)
+		if ipNet == nil {
+			invalid = append(invalid, item)
+			continue
+		}
+		list.nets = append(list.nets, ipNet)
+	}
+	return list, invalid
+}
+
+func (this *IpList) Len() int {
+	return len(this.nets)
+}
+
+func (this *IpList) Contains(ip net.IP) bool {
+	if ip == nil {
+		return false
+	}
+	for _, ipNet := range this.nets {
+		if ipNet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseIpNet 解析单个IP或CIDR网段，单个IP视为掩码全1的网段
+func ParseIpNet(item string) *net.IPNet {
+	if strings.Contains(item, "/") {
+		_, ipNet, err := net.ParseCIDR(item)
+		if err != nil {
+			return nil
+		}
+		return ipNet
+	}
+
+	ip := net.ParseIP(item)
+	if ip == nil {
+		return nil
+	}
+	bits := 128
+	if ip.To4() != nil {
+		ip = ip.To4()
+		bits = 32
+	}
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)}
+}
+
+// ParseIp 从ip:port格式的地址中取出IP
+func ParseIp(remoteAddr string) net.IP {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+	return net.ParseIP(host)
+}
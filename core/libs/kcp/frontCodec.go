@@ -0,0 +1,11 @@
+package kcp
+
+import (
+	"github.com/xtaci/kcp-go/v5"
+	"github.com/yicaoyimuys/GoGameServer/core/libs/frame"
+	"github.com/yicaoyimuys/GoGameServer/core/libs/sessions"
+)
+
+func NewFrontCodec(rw *kcp.UDPSession, protocol *frame.Protocol) sessions.Codec {
+	return frame.NewStreamCodec(rw, protocol)
+}
@@ -0,0 +1,177 @@
+package kcp
+
+import (
+	"crypto/ed25519"
+
+	"github.com/xtaci/kcp-go/v5"
+	"github.com/yicaoyimuys/GoGameServer/core/libs/admission"
+	"github.com/yicaoyimuys/GoGameServer/core/libs/frame"
+	"github.com/yicaoyimuys/GoGameServer/core/libs/guid"
+	"github.com/yicaoyimuys/GoGameServer/core/libs/logger"
+	"github.com/yicaoyimuys/GoGameServer/core/libs/sessions"
+	"github.com/yicaoyimuys/GoGameServer/core/libs/stack"
+	"go.uber.org/zap"
+)
+
+const (
+	//极速模式: nodelay, interval, resend, nc
+	noDelay    = 1
+	interval   = 10
+	resend     = 2
+	noCongest  = 1
+	windowSize = 256
+	mtu        = 1350
+)
+
+type Server struct {
+	port string
+	guid *guid.Guid
+
+	maxFrameSize      int
+	compressThreshold int
+	cryptoKey         string
+	signKey           ed25519.PrivateKey
+
+	admission *admission.Admission
+
+	sessionCreateHandle     sessions.FrontSessionCreateHandle
+	sessionReceiveMsgHandle sessions.FrontSessionReceiveMsgHandle
+}
+
+func NewServer(port string, serviceId int) *Server {
+	server := &Server{
+		port: port,
+		guid: guid.NewGuid(uint16(serviceId)),
+	}
+	return server
+}
+
+// SetGuid 多个前端Server同时运行时需共用一个Guid，避免SessionID重复
+func (this *Server) SetGuid(guid *guid.Guid) {
+	this.guid = guid
+}
+
+// SetCrypto 开启后客户端需先进行密钥交换，之后的消息全部加密
+// cryptoKey为服务器签名私钥文件，客户端使用对应公钥校验服务器
+func (this *Server) SetCrypto(cryptoKey string) {
+	this.cryptoKey = cryptoKey
+}
+
+func (this *Server) SetAdmission(admission *admission.Admission) {
+	this.admission = admission
+}
+
+func (this *Server) SetMaxFrameSize(maxFrameSize int) {
+	this.maxFrameSize = maxFrameSize
+}
+
+func (this *Server) SetCompressThreshold(compressThreshold int) {
+	this.compressThreshold = compressThreshold
+}
+
+func (this *Server) SetSessionCreateHandle(handle sessions.FrontSessionCreateHandle) {
+	this.sessionCreateHandle = handle
+}
+
+func (this *Server) SetSessionReceiveMsgHandle(handle sessions.FrontSessionReceiveMsgHandle) {
+	this.sessionReceiveMsgHandle = handle
+}
+
+// Start 密钥加载失败或端口监听失败时返回错误
+func (this *Server) Start() error {
+	logger.Info("Front Start Kcp", zap.String("Port", this.port))
+
+	if this.cryptoKey != "" {
+		signKey, err := frame.LoadSignKey(this.cryptoKey)
+		if err != nil {
+			return err
+		}
+		this.signKey = signKey
+	}
+
+	listener, err := kcp.ListenWithOptions("0.0.0.0:"+this.port, nil, 0, 0)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		defer stack.TryError()
+		defer listener.Close()
+
+		logger.Info("Kcp Waiting Client Connect...")
+		for {
+			conn, err := listener.AcceptKCP()
+			if err != nil {
+				stack.CheckError(err)
+				return
+			}
+
+			go this.handleConnect(conn)
+		}
+	}()
+	return nil
+}
+
+func (this *Server) StartPing(overTime int, interval int) {
+	if overTime <= 0 {
+		overTime = 15
+	}
+	if interval <= 0 {
+		interval = 2
+	}
+	sessions.FrontSessionOpenPing(int64(overTime), int64(interval))
+	logger.Info("Session超时时间设置", zap.Int("OverTime", overTime), zap.Int("Interval", interval))
+}
+
+func (this *Server) handleConnect(conn *kcp.UDPSession) {
+	//捕获异常
+	defer stack.TryError()
+
+	remoteAddr := conn.RemoteAddr().String()
+
+	//准入检测
+	if this.admission != nil {
+		release, err := this.admission.Accept(remoteAddr)
+		if err != nil {
+			logger.Warn("拒绝连接", zap.String("RemoteAddr", remoteAddr), zap.Error(err))
+			conn.Close()
+			return
+		}
+		defer release()
+	}
+
+	//Kcp参数设置
+	conn.SetStreamMode(true)
+	conn.SetWriteDelay(false)
+	conn.SetNoDelay(noDelay, interval, resend, noCongest)
+	conn.SetWindowSize(windowSize, windowSize)
+	conn.SetMtu(mtu)
+	conn.SetACKNoDelay(true)
+
+	//Session创建
+	sessionId := this.guid.NewID()
+	protocol := frame.NewProtocol(this.maxFrameSize, this.compressThreshold)
+	protocol.SetCrypto(this.signKey)
+	sessionCodec := NewFrontCodec(conn, protocol)
+	session := sessions.NewFontSession(sessionId, sessionCodec)
+	session.SetRemoteAddr(remoteAddr)
+	this.addFontSession(session)
+}
+
+func (this *Server) addFontSession(session *sessions.FrontSession) {
+	sessions.AddFrontSession(session)
+	if this.sessionCreateHandle != nil {
+		this.sessionCreateHandle(session)
+	}
+	if this.sessionReceiveMsgHandle != nil {
+		session.SetMsgHandle(this.sessionReceiveMsgHandle)
+	}
+
+	defer session.Close()
+	for {
+		msg, err := session.Receive()
+		if err != nil || msg == nil {
+			break
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package protos
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+var ErrMsgId = errors.New("protos: unknown msg id")
+
+// JsonMsg 调试用的JSON消息格式，例如: {"id":2001,"body":{"account":"test"}}
+type JsonMsg struct {
+	ID   uint16          `json:"id"`
+	Body json.RawMessage `json:"body"`
+}
+
+// JsonToProtoMsg JSON消息转换为MarshalProtoMsg的格式
+func JsonToProtoMsg(data []byte) ([]byte, error) {
+	jsonMsg := &JsonMsg{}
+	err := json.Unmarshal(data, jsonMsg)
+	if err != nil {
+		return nil, err
+	}
+
+	msgBody := GetMsgObject(jsonMsg.ID)
+	if msgBody == nil {
+		return nil, ErrMsgId
+	}
+	if len(jsonMsg.Body) > 0 {
+		err = protojson.Unmarshal(jsonMsg.Body, msgBody)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	body, err := proto.Marshal(msgBody)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]byte, 2+len(body))
+	binary.BigEndian.PutUint16(result[:2], jsonMsg.ID)
+	copy(result[2:], body)
+	return result, nil
+}
+
+// ProtoMsgToJson MarshalProtoMsg格式的消息转换为JSON
+func ProtoMsgToJson(msg []byte) ([]byte, error) {
+	protoMsg := UnmarshalProtoMsg(msg)
+	if protoMsg == NullProtoMsg {
+		return nil, ErrMsgId
+	}
+
+	body, err := protojson.Marshal(protoMsg.Body)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(&JsonMsg{
+		ID:   protoMsg.ID,
+		Body: body,
+	})
+}
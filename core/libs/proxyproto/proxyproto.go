@@ -0,0 +1,197 @@
+package proxyproto
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	//读取PROXY协议头的超时时间
+	headerTimeout = 5 * time.Second
+	//v1协议头最大长度
+	v1MaxLen = 107
+)
+
+var (
+	v1Prefix    = []byte("PROXY ")
+	v2Signature = []byte("\r\n\r\n\x00\r\nQUIT\n")
+
+	ErrNoHeader      = errors.New("proxyproto: no proxy header")
+	ErrInvalidHeader = errors.New("proxyproto: invalid proxy header")
+)
+
+// Listener 负载均衡转发的连接，在连接开头带有PROXY协议头(v1或v2)，从中取出客户端的真实地址
+type Listener struct {
+	net.Listener
+}
+
+func NewListener(listener net.Listener) *Listener {
+	return &Listener{Listener: listener}
+}
+
+func (this *Listener) Accept() (net.Conn, error) {
+	conn, err := this.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	return NewConn(conn), nil
+}
+
+// Conn 协议头在第一次Read或RemoteAddr时读取，不阻塞Accept
+type Conn struct {
+	net.Conn
+	reader *bufio.Reader
+
+	once       sync.Once
+	remoteAddr net.Addr
+	err        error
+}
+
+func NewConn(conn net.Conn) *Conn {
+	return &Conn{
+		Conn:   conn,
+		reader: bufio.NewReader(conn),
+	}
+}
+
+func (this *Conn) Read(b []byte) (int, error) {
+	this.once.Do(this.readHeader)
+	if this.err != nil {
+		return 0, this.err
+	}
+	return this.reader.Read(b)
+}
+
+func (this *Conn) RemoteAddr() net.Addr {
+	this.once.Do(this.readHeader)
+	if this.remoteAddr != nil {
+		return this.remoteAddr
+	}
+	return this.Conn.RemoteAddr()
+}
+
+// Err 读取协议头的错误，没有协议头或格式错误时不为nil
+func (this *Conn) Err() error {
+	this.once.Do(this.readHeader)
+	return this.err
+}
+
+func (this *Conn) readHeader() {
+	this.Conn.SetReadDeadline(time.Now().Add(headerTimeout))
+	defer this.Conn.SetReadDeadline(time.Time{})
+
+	this.remoteAddr, this.err = ReadHeader(this.reader)
+}
+
+// CheckConn 检测连接的协议头是否正确，TLS连接检测其底层连接
+func CheckConn(conn net.Conn) error {
+	if tlsConn, ok := conn.(interface{ NetConn() net.Conn }); ok {
+		conn = tlsConn.NetConn()
+	}
+	if proxyConn, ok := conn.(*Conn); ok {
+		return proxyConn.Err()
+	}
+	return nil
+}
+
+// ReadHeader 读取PROXY协议头，返回客户端地址，UNKNOWN或LOCAL时返回nil
+func ReadHeader(reader *bufio.Reader) (net.Addr, error) {
+	head, err := reader.Peek(len(v1Prefix))
+	if err != nil {
+		return nil, err
+	}
+	if bytes.Equal(head, v1Prefix) {
+		return readV1(reader)
+	}
+
+	head, err = reader.Peek(len(v2Signature))
+	if err != nil {
+		return nil, err
+	}
+	if bytes.Equal(head, v2Signature) {
+		return readV2(reader)
+	}
+	return nil, ErrNoHeader
+}
+
+// 例如: PROXY TCP4 192.168.0.1 192.168.0.11 56324 443\r\n
+func readV1(reader *bufio.Reader) (net.Addr, error) {
+	line := make([]byte, 0, v1MaxLen)
+	for {
+		b, err := reader.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		line = append(line, b)
+		if b == '\n' {
+			break
+		}
+		if len(line) >= v1MaxLen {
+			return nil, ErrInvalidHeader
+		}
+	}
+	if !bytes.HasSuffix(line, []byte("\r\n")) {
+		return nil, ErrInvalidHeader
+	}
+
+	fields := strings.Split(string(line[:len(line)-2]), " ")
+	if len(fields) >= 2 && fields[1] == "UNKNOWN" {
+		return nil, nil
+	}
+	if len(fields) != 6 || (fields[1] != "TCP4" && fields[1] != "TCP6") {
+		return nil, ErrInvalidHeader
+	}
+	ip := net.ParseIP(fields[2])
+	port, err := strconv.Atoi(fields[4])
+	if ip == nil || err != nil || port < 0 || port > 65535 {
+		return nil, ErrInvalidHeader
+	}
+	return &net.TCPAddr{IP: ip, Port: port}, nil
+}
+
+// 12字节签名 + 版本/命令 + 协议族 + 2字节地址长度 + 地址
+func readV2(reader *bufio.Reader) (net.Addr, error) {
+	head := make([]byte, 16)
+	if _, err := io.ReadFull(reader, head); err != nil {
+		return nil, err
+	}
+	if head[12]>>4 != 2 {
+		return nil, ErrInvalidHeader
+	}
+	command := head[12] & 0x0F
+	family := head[13] >> 4
+	length := int(binary.BigEndian.Uint16(head[14:16]))
+
+	data := make([]byte, length)
+	if _, err := io.ReadFull(reader, data); err != nil {
+		return nil, err
+	}
+
+	//LOCAL为负载均衡自身的健康检查等连接
+	if command == 0 {
+		return nil, nil
+	}
+	if command != 1 {
+		return nil, ErrInvalidHeader
+	}
+
+	if family == 1 && length >= 12 {
+		ip := net.IP(data[0:4])
+		port := binary.BigEndian.Uint16(data[8:10])
+		return &net.TCPAddr{IP: ip, Port: int(port)}, nil
+	} else if family == 2 && length >= 36 {
+		ip := net.IP(data[0:16])
+		port := binary.BigEndian.Uint16(data[32:34])
+		return &net.TCPAddr{IP: ip, Port: int(port)}, nil
+	}
+	//UNIX等其他协议族使用原地址
+	return nil, nil
+}
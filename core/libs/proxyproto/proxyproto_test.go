@@ -0,0 +1,151 @@
+package proxyproto
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"testing"
+)
+
+// v2协议头：command 0为LOCAL、1为PROXY，family 1为IPv4、2为IPv6、3为UNIX
+func v2Header(version byte, command byte, family byte, addr []byte) []byte {
+	buf := bytes.NewBuffer(nil)
+	buf.Write(v2Signature)
+	buf.WriteByte(version<<4 | command)
+	buf.WriteByte(family<<4 | 1)
+	binary.Write(buf, binary.BigEndian, uint16(len(addr)))
+	buf.Write(addr)
+	return buf.Bytes()
+}
+
+func v2Addr(src net.IP, dst net.IP, srcPort uint16, dstPort uint16) []byte {
+	buf := bytes.NewBuffer(nil)
+	buf.Write(src)
+	buf.Write(dst)
+	binary.Write(buf, binary.BigEndian, srcPort)
+	binary.Write(buf, binary.BigEndian, dstPort)
+	return buf.Bytes()
+}
+
+func TestReadHeader(t *testing.T) {
+	ipv4Addr := v2Addr(net.ParseIP("192.168.0.1").To4(), net.ParseIP("192.168.0.11").To4(), 56324, 443)
+	ipv6Addr := v2Addr(net.ParseIP("2001:db8::1"), net.ParseIP("2001:db8::2"), 56324, 443)
+
+	tests := []struct {
+		name    string
+		data    []byte
+		addr    string //空为使用原地址
+		err     error
+		anyErr  bool
+		payload string
+	}{
+		{name: "v1 tcp4", data: []byte("PROXY TCP4 192.168.0.1 192.168.0.11 56324 443\r\nhello"), addr: "192.168.0.1:56324", payload: "hello"},
+		{name: "v1 tcp6", data: []byte("PROXY TCP6 2001:db8::1 2001:db8::2 56324 443\r\nhello"), addr: "[2001:db8::1]:56324", payload: "hello"},
+		{name: "v1 unknown", data: []byte("PROXY UNKNOWN\r\nhello"), payload: "hello"},
+		{name: "v1 unknown with addr", data: []byte("PROXY UNKNOWN 192.168.0.1 192.168.0.11 56324 443\r\nhello"), payload: "hello"},
+		{name: "v1 bad ip", data: []byte("PROXY TCP4 192.168.0.300 192.168.0.11 56324 443\r\n"), err: ErrInvalidHeader},
+		{name: "v1 bad port", data: []byte("PROXY TCP4 192.168.0.1 192.168.0.11 65536 443\r\n"), err: ErrInvalidHeader},
+		{name: "v1 bad protocol", data: []byte("PROXY UDP4 192.168.0.1 192.168.0.11 56324 443\r\n"), err: ErrInvalidHeader},
+		{name: "v1 missing field", data: []byte("PROXY TCP4 192.168.0.1 192.168.0.11 56324\r\n"), err: ErrInvalidHeader},
+		{name: "v1 missing cr", data: []byte("PROXY TCP4 192.168.0.1 192.168.0.11 56324 443\n"), err: ErrInvalidHeader},
+		{name: "v1 too long", data: []byte("PROXY TCP4 " + strings.Repeat("1", v1MaxLen) + "\r\n"), err: ErrInvalidHeader},
+		{name: "v1 truncated", data: []byte("PROXY TCP4 192.168.0.1 192.16"), err: io.EOF},
+		{name: "v2 tcp4", data: append(v2Header(2, 1, 1, ipv4Addr), "hello"...), addr: "192.168.0.1:56324", payload: "hello"},
+		{name: "v2 tcp6", data: append(v2Header(2, 1, 2, ipv6Addr), "hello"...), addr: "[2001:db8::1]:56324", payload: "hello"},
+		{name: "v2 local", data: append(v2Header(2, 0, 0, nil), "hello"...), payload: "hello"},
+		{name: "v2 local with addr", data: append(v2Header(2, 0, 1, ipv4Addr), "hello"...), payload: "hello"},
+		{name: "v2 unix", data: append(v2Header(2, 1, 3, make([]byte, 216)), "hello"...), payload: "hello"},
+		{name: "v2 bad version", data: v2Header(1, 1, 1, ipv4Addr), err: ErrInvalidHeader},
+		{name: "v2 bad command", data: v2Header(2, 2, 1, ipv4Addr), err: ErrInvalidHeader},
+		{name: "v2 truncated head", data: v2Header(2, 1, 1, ipv4Addr)[:14], anyErr: true},
+		{name: "v2 truncated addr", data: v2Header(2, 1, 1, ipv4Addr)[:20], err: io.ErrUnexpectedEOF},
+		{name: "no header", data: []byte("GET / HTTP/1.1\r\n\r\n"), err: ErrNoHeader},
+		{name: "short", data: []byte("PRO"), err: io.EOF},
+		{name: "empty", data: nil, err: io.EOF},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			reader := bufio.NewReader(bytes.NewReader(test.data))
+			addr, err := ReadHeader(reader)
+			if test.err != nil || test.anyErr {
+				if err == nil {
+					t.Fatalf("err = nil, addr = %v", addr)
+				}
+				if test.err != nil && !errors.Is(err, test.err) {
+					t.Fatalf("err = %v, want %v", err, test.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("err = %v", err)
+			}
+
+			if test.addr == "" {
+				if addr != nil {
+					t.Fatalf("addr = %v, want nil", addr)
+				}
+			} else if addr == nil || addr.String() != test.addr {
+				t.Fatalf("addr = %v, want %s", addr, test.addr)
+			}
+
+			//协议头之后的数据不受影响
+			payload, _ := io.ReadAll(reader)
+			if string(payload) != test.payload {
+				t.Fatalf("payload = %q, want %q", payload, test.payload)
+			}
+		})
+	}
+}
+
+func TestConn(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		addr string //空为使用原地址
+		err  error
+	}{
+		{name: "proxy", data: "PROXY TCP4 192.168.0.1 192.168.0.11 56324 443\r\n", addr: "192.168.0.1:56324"},
+		{name: "unknown", data: "PROXY UNKNOWN\r\n"},
+		{name: "no header", data: "GET / HTTP/1.1\r\n", err: ErrNoHeader},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client, server := net.Pipe()
+			defer client.Close()
+			go func() {
+				client.Write([]byte(test.data + "hello"))
+			}()
+
+			conn := NewConn(server)
+			defer conn.Close()
+
+			if err := CheckConn(conn); !errors.Is(err, test.err) {
+				t.Fatalf("CheckConn = %v, want %v", err, test.err)
+			}
+			if test.err != nil {
+				if _, err := conn.Read(make([]byte, 5)); !errors.Is(err, test.err) {
+					t.Fatalf("Read err = %v, want %v", err, test.err)
+				}
+				return
+			}
+
+			addr := test.addr
+			if addr == "" {
+				addr = server.RemoteAddr().String()
+			}
+			if conn.RemoteAddr().String() != addr {
+				t.Fatalf("RemoteAddr = %v, want %s", conn.RemoteAddr(), addr)
+			}
+			buf := make([]byte, 5)
+			if _, err := io.ReadFull(conn, buf); err != nil || string(buf) != "hello" {
+				t.Fatalf("Read = %q, %v", buf, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// TokenBucket 令牌桶，每秒补充rate个令牌，最多保存burst个
+type TokenBucket struct {
+	rate     float64
+	burst    float64
+	tokens   float64
+	lastTime time.Time
+	mutex    sync.Mutex
+}
+
+func NewTokenBucket(rate float64, burst float64) *TokenBucket {
+	return &TokenBucket{
+		rate:     rate,
+		burst:    burst,
+		tokens:   burst,
+		lastTime: time.Now(),
+	}
+}
+
+// SetRate 修改速率和容量，已有的令牌保留
+func (this *TokenBucket) SetRate(rate float64, burst float64) {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	this.refill(time.Now())
+	this.rate = rate
+	this.burst = burst
+	if this.tokens > burst {
+		this.tokens = burst
+	}
+}
+
+// Allow 取出一个令牌，令牌不足时返回false
+func (this *TokenBucket) Allow() bool {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	this.refill(time.Now())
+	if this.tokens < 1 {
+		return false
+	}
+	this.tokens--
+	return true
+}
+
+func (this *TokenBucket) refill(now time.Time) {
+	elapsed := now.Sub(this.lastTime).Seconds()
+	this.lastTime = now
+	if elapsed <= 0 {
+		return
+	}
+	this.tokens += elapsed * this.rate
+	if this.tokens > this.burst {
+		this.tokens = this.burst
+	}
+}
@@ -56,6 +56,12 @@ func (this *Client) HSet(key, field string, value interface{}) *redis.BoolCmd {
 	return this.redisClient.HSet(key, field, value)
 }
 
+// HSetNX 字段不存在时才设置，返回是否设置成功
+func (this *Client) HSetNX(key, field string, value interface{}) *redis.BoolCmd {
+	key = this.GetKey(key)
+	return this.redisClient.HSetNX(key, field, value)
+}
+
 func (this *Client) HGet(key, field string) *redis.StringCmd {
 	key = this.GetKey(key)
 	return this.redisClient.HGet(key, field)
@@ -65,3 +71,57 @@ func (this *Client) HGetAll(key string) *redis.StringStringMapCmd {
 	key = this.GetKey(key)
 	return this.redisClient.HGetAll(key)
 }
+
+func (this *Client) Del(key string) *redis.IntCmd {
+	key = this.GetKey(key)
+	return this.redisClient.Del(key)
+}
+
+func (this *Client) Expire(key string, expiration time.Duration) *redis.BoolCmd {
+	key = this.GetKey(key)
+	return this.redisClient.Expire(key, expiration)
+}
+
+// GetDel 读取并删除，并发调用时只有一方能读到值
+func (this *Client) GetDel(key string) *redis.StringCmd {
+	key = this.GetKey(key)
+	var cmd *redis.StringCmd
+	this.redisClient.TxPipelined(func(pipe redis.Pipeliner) error {
+		cmd = pipe.Get(key)
+		pipe.Del(key)
+		return nil
+	})
+	return cmd
+}
+
+func (this *Client) RPush(key string, values ...interface{}) *redis.IntCmd {
+	key = this.GetKey(key)
+	return this.redisClient.RPush(key, values...)
+}
+
+func (this *Client) LTrim(key string, start, stop int64) *redis.StatusCmd {
+	key = this.GetKey(key)
+	return this.redisClient.LTrim(key, start, stop)
+}
+
+func (this *Client) LRange(key string, start, stop int64) *redis.StringSliceCmd {
+	key = this.GetKey(key)
+	return this.redisClient.LRange(key, start, stop)
+}
+
+// LTake 读取整个列表并删除，读取和删除之间不会插入新的元素
+func (this *Client) LTake(key string) *redis.StringSliceCmd {
+	key = this.GetKey(key)
+	var cmd *redis.StringSliceCmd
+	this.redisClient.TxPipelined(func(pipe redis.Pipeliner) error {
+		cmd = pipe.LRange(key, 0, -1)
+		pipe.Del(key)
+		return nil
+	})
+	return cmd
+}
+
+func (this *Client) Incr(key string) *redis.IntCmd {
+	key = this.GetKey(key)
+	return this.redisClient.Incr(key)
+}
@@ -3,6 +3,7 @@ package rpc
 import (
 	"errors"
 	"io"
+	"math/rand"
 	"net"
 	"net/rpc"
 	"net/rpc/jsonrpc"
@@ -14,8 +15,6 @@ import (
 	"github.com/yicaoyimuys/GoGameServer/core/libs/logger"
 	"github.com/yicaoyimuys/GoGameServer/core/libs/timer"
 	"go.uber.org/zap"
-
-	"github.com/spf13/cast"
 )
 
 type Client struct {
@@ -23,6 +22,8 @@ type Client struct {
 	serviceName  string
 
 	services      []string
+	weights       map[string]int
+	ring          *hash.Ring
 	servicesMutex sync.Mutex
 
 	links     map[string]*rpc.Client
@@ -41,18 +42,41 @@ func NewClient(consulClient *consul.Client, serviceName string) *Client {
 }
 
 func (this *Client) loop() {
-	timer.SetTimeOut(5*1000, this.initServices)
+	timer.DoTimer(5*1000, this.refreshServices)
 }
 
 func (this *Client) initServices() {
-	this.servicesMutex.Lock()
-	this.services = this.consulClient.GetServices(this.serviceName)
-	this.servicesMutex.Unlock()
+	this.updateServices(this.consulClient.GetServiceInfos(this.serviceName))
 
 	this.initLinks()
 	this.traceServices()
 }
 
+// 定时同步服务列表，节点增减时只有一致性哈希环上相邻的Key会重新分配
+func (this *Client) refreshServices() {
+	infos := this.consulClient.GetServiceInfos(this.serviceName)
+	if len(infos) == 0 {
+		//consul异常时保留原列表
+		return
+	}
+	this.updateServices(infos)
+}
+
+func (this *Client) updateServices(infos []consul.ServiceInfo) {
+	services := []string{}
+	weights := make(map[string]int)
+	for _, info := range infos {
+		services = append(services, info.Addr())
+		weights[info.Addr()] = info.Weight
+	}
+
+	this.servicesMutex.Lock()
+	this.services = services
+	this.weights = weights
+	this.ring = hash.NewRing(hash.DefaultReplicas, weights)
+	this.servicesMutex.Unlock()
+}
+
 func (this *Client) initLinks() {
 	if len(this.services) == 0 {
 		timer.SetTimeOut(300, this.initServices)
@@ -84,6 +108,10 @@ func (this *Client) removeService(service string) {
 			this.services = append(this.services[:index], this.services[index+1:]...)
 		}
 	}
+	if _, ok := this.weights[service]; ok {
+		delete(this.weights, service)
+		this.ring = hash.NewRing(hash.DefaultReplicas, this.weights)
+	}
 	this.servicesMutex.Unlock()
 
 	this.traceServices()
@@ -91,16 +119,47 @@ func (this *Client) removeService(service string) {
 
 func (this *Client) getServiceByFlag(flag string) string {
 	this.servicesMutex.Lock()
-	service := ""
-	servicesLen := len(this.services)
-	if servicesLen > 0 {
-		num := hash.GetHash([]byte(flag))
-		index := int(num % uint32(servicesLen))
-		service = this.services[index]
+	ring := this.ring
+	this.servicesMutex.Unlock()
+
+	if ring == nil {
+		return ""
+	}
+	return ring.Get(flag)
+}
+
+func (this *Client) getServiceByRandom() string {
+	this.servicesMutex.Lock()
+	defer this.servicesMutex.Unlock()
+
+	if len(this.services) == 0 {
+		return ""
+	}
+	return this.services[rand.Intn(len(this.services))]
+}
+
+// GetServiceWeights 当前服务列表及其一致性哈希权重
+func (this *Client) GetServiceWeights() map[string]int {
+	this.servicesMutex.Lock()
+	defer this.servicesMutex.Unlock()
+
+	weights := make(map[string]int, len(this.weights))
+	for service, weight := range this.weights {
+		weights[service] = weight
 	}
+	return weights
+}
+
+// GetMovedKeys 扩缩容前预览：服务列表变为weights后，keys中会分配到其他服务的Key
+func (this *Client) GetMovedKeys(keys []string, weights map[string]int) []hash.MovedKey {
+	this.servicesMutex.Lock()
+	ring := this.ring
 	this.servicesMutex.Unlock()
 
-	return service
+	if ring == nil {
+		ring = hash.NewRing(hash.DefaultReplicas, nil)
+	}
+	return ring.MovedKeys(hash.NewRing(hash.DefaultReplicas, weights), keys)
 }
 
 func (this *Client) getLink(service string) *rpc.Client {
@@ -148,10 +207,12 @@ func (this *Client) removeLink(service string) {
 }
 
 func (this *Client) Call(serviceMethod string, args interface{}, reply interface{}, flag string) error {
+	var service string
 	if flag == "" {
-		flag = cast.ToString(time.Now().Unix())
+		service = this.getServiceByRandom()
+	} else {
+		service = this.getServiceByFlag(flag)
 	}
-	service := this.getServiceByFlag(flag)
 	if service == "" {
 		return errors.New("RpcServer No Exists")
 	}
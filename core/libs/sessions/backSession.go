@@ -1,6 +1,7 @@
 package sessions
 
 import (
+	"encoding/binary"
 	"sync"
 	"sync/atomic"
 
@@ -9,9 +10,10 @@ import (
 )
 
 type BackSession struct {
-	id        string
-	sessionId uint64
-	stream    *ipc.Stream
+	id          string
+	sessionId   uint64
+	stream      *ipc.Stream
+	streamMutex sync.RWMutex
 
 	closeFlag          int32
 	closeChan          chan int
@@ -19,11 +21,22 @@ type BackSession struct {
 	firstCloseCallback *closeCallback
 	lastCloseCallback  *closeCallback
 
-	recvChan  chan []byte
+	recvChan  chan *backMsg
 	recvMutex sync.Mutex
 
-	msgHandle func(session *BackSession, msgBody []byte)
-	userId    uint64
+	msgHandle  func(session *BackSession, msgBody []byte)
+	userId     uint64
+	remoteAddr atomic.Value
+
+	//当前处理中的消息，只在消息处理协程中、处理返回前有效
+	requestSeq   uint32
+	requestMsgId uint16
+}
+
+type backMsg struct {
+	data       []byte
+	requestSeq uint32
+	closed     bool
 }
 
 func NewBackSession(id string, sessionId uint64, stream *ipc.Stream) *BackSession {
@@ -31,7 +44,7 @@ func NewBackSession(id string, sessionId uint64, stream *ipc.Stream) *BackSessio
 		id:        id,
 		sessionId: sessionId,
 		stream:    stream,
-		recvChan:  make(chan []byte, 100),
+		recvChan:  make(chan *backMsg, 100),
 		closeChan: make(chan int),
 	}
 	stream.AddSession(session)
@@ -48,23 +61,60 @@ func (this *BackSession) ID() string {
 }
 
 func (this *BackSession) UserID() uint64 {
-	return this.userId
+	return atomic.LoadUint64(&this.userId)
 }
 
 func (this *BackSession) SetUserId(userId uint64) {
-	this.userId = userId
+	atomic.StoreUint64(&this.userId, userId)
+}
+
+// IsLogin 是否已绑定用户
+func (this *BackSession) IsLogin() bool {
+	return this.UserID() != 0
+}
+
+// RemoteAddr 客户端地址，由connector随消息转发
+func (this *BackSession) RemoteAddr() string {
+	value, _ := this.remoteAddr.Load().(string)
+	return value
+}
+
+func (this *BackSession) SetRemoteAddr(remoteAddr string) {
+	if remoteAddr != "" && remoteAddr != this.RemoteAddr() {
+		this.remoteAddr.Store(remoteAddr)
+	}
+}
+
+// Receive requestSeq为客户端的请求序号，0为不需要
+// Receive 不阻塞stream的接收，处理不过来时断开客户端并关闭Session
+func (this *BackSession) Receive(data []byte, requestSeq uint32) error {
+	return this.receive(&backMsg{data: data, requestSeq: requestSeq})
 }
 
-func (this *BackSession) Receive(data []byte) error {
+// ReceiveClose 客户端已断开，之前收到的消息处理完后关闭Session
+func (this *BackSession) ReceiveClose() error {
+	return this.receive(&backMsg{closed: true})
+}
+
+func (this *BackSession) receive(msg *backMsg) error {
 	this.recvMutex.Lock()
 	if this.IsClosed() {
 		this.recvMutex.Unlock()
 		return ErrClosed
 	}
 
-	this.recvChan <- data
+	select {
+	case this.recvChan <- msg:
+		this.recvMutex.Unlock()
+		return nil
+	default:
+	}
 	this.recvMutex.Unlock()
-	return nil
+
+	//之后的消息已无法保证顺序
+	this.Kick("recvQueueFull", nil)
+	this.Close()
+	return ErrRecvQueueFull
 }
 
 func (this *BackSession) Send(data []byte) error {
@@ -72,9 +122,177 @@ func (this *BackSession) Send(data []byte) error {
 		return ErrClosed
 	}
 
+	this.streamMutex.RLock()
+	defer this.streamMutex.RUnlock()
+
+	if this.stream == nil {
+		return ErrClosed
+	}
 	return this.stream.Send([]uint64{this.sessionId}, data)
 }
 
+func (this *BackSession) sendControl(msg *ipc.Res) error {
+	if this.IsClosed() {
+		return ErrClosed
+	}
+
+	this.streamMutex.RLock()
+	defer this.streamMutex.RUnlock()
+
+	if this.stream == nil {
+		return ErrClosed
+	}
+	return this.stream.SendControl([]uint64{this.sessionId}, msg)
+}
+
+// Kick connector发送data(可为nil)后断开客户端连接，不再等待断线重连
+func (this *BackSession) Kick(reason string, data []byte) error {
+	return this.sendControl(&ipc.Res{
+		Control: ipc.ControlType_CONTROL_KICK,
+		Reason:  reason,
+		Data:    data,
+	})
+}
+
+// BindUser 绑定用户ID，同时绑定到connector中的Session
+func (this *BackSession) BindUser(userId uint64) error {
+	this.SetUserId(userId)
+	return this.sendControl(&ipc.Res{
+		Control: ipc.ControlType_CONTROL_BIND_USER,
+		UserId:  userId,
+	})
+}
+
+// SetAttrs 设置connector中Session的属性，值为空时删除该属性
+func (this *BackSession) SetAttrs(attrs map[string]string) error {
+	return this.sendControl(&ipc.Res{
+		Control: ipc.ControlType_CONTROL_SET_ATTRS,
+		Attrs:   attrs,
+	})
+}
+
+func (this *BackSession) SetAttr(key string, value string) error {
+	return this.SetAttrs(map[string]string{key: value})
+}
+
+func (this *BackSession) ClearAttrs() error {
+	return this.sendControl(&ipc.Res{
+		Control: ipc.ControlType_CONTROL_CLEAR_ATTRS,
+	})
+}
+
+// Pin 之后发送给serviceName的消息都发送到service(服务器地址)
+func (this *BackSession) Pin(serviceName string, service string) error {
+	return this.sendControl(&ipc.Res{
+		Control:     ipc.ControlType_CONTROL_PIN,
+		ServiceName: serviceName,
+		Service:     service,
+	})
+}
+
+// Forward 通过connector代替客户端将data发送给其他后端服务，对方收到的Session与客户端直接发送时相同
+// service为空时使用已分配的服务器，未分配时由connector分配
+func (this *BackSession) Forward(serviceName string, service string, data []byte) error {
+	return this.sendControl(&ipc.Res{
+		Control:     ipc.ControlType_CONTROL_FORWARD,
+		ServiceName: serviceName,
+		Service:     service,
+		Data:        data,
+	})
+}
+
+// JoinGroup 在connector中加入分组，通过分组发送的消息由connector转发
+func (this *BackSession) JoinGroup(group string) error {
+	return this.sendControl(&ipc.Res{
+		Control: ipc.ControlType_CONTROL_GROUP_JOIN,
+		Group:   group,
+	})
+}
+
+func (this *BackSession) LeaveGroup(group string) error {
+	return this.sendControl(&ipc.Res{
+		Control: ipc.ControlType_CONTROL_GROUP_LEAVE,
+		Group:   group,
+	})
+}
+
+// Reply 回复当前处理中的消息，客户端请求带有序号时回复中带上相同的序号
+// 只能在消息处理协程中、处理返回前调用，之后Session已在处理其他消息，需先取出RequestSeq再使用SendResponse
+func (this *BackSession) Reply(data []byte) error {
+	return this.SendResponse(data, this.requestSeq)
+}
+
+// SendResponse 回复指定序号的请求，requestSeq为0时与Send相同
+func (this *BackSession) SendResponse(data []byte, requestSeq uint32) error {
+	if requestSeq == 0 {
+		return this.Send(data)
+	}
+	if this.IsClosed() {
+		return ErrClosed
+	}
+
+	this.streamMutex.RLock()
+	defer this.streamMutex.RUnlock()
+
+	if this.stream == nil {
+		return ErrClosed
+	}
+	return this.stream.SendResponse(this.sessionId, data, requestSeq)
+}
+
+// RequestSeq 当前处理中消息的请求序号，与Reply相同只在消息处理协程中有效
+func (this *BackSession) RequestSeq() uint32 {
+	return this.requestSeq
+}
+
+// RequestMsgId 当前处理中消息的ID，与Reply相同只在消息处理协程中有效
+func (this *BackSession) RequestMsgId() uint16 {
+	return this.requestMsgId
+}
+
+// SendReliable 发送需客户端确认的消息，断线重连后也保证只送达一次
+func (this *BackSession) SendReliable(data []byte) error {
+	if this.IsClosed() {
+		return ErrClosed
+	}
+
+	this.streamMutex.RLock()
+	defer this.streamMutex.RUnlock()
+
+	if this.stream == nil {
+		return ErrClosed
+	}
+	return this.stream.SendReliable([]uint64{this.sessionId}, data)
+}
+
+// IsStream Session当前是否通过该stream收发消息
+func (this *BackSession) IsStream(stream *ipc.Stream) bool {
+	this.streamMutex.RLock()
+	defer this.streamMutex.RUnlock()
+
+	return this.stream == stream
+}
+
+// SetStream 断线重连到其他connector后，改为通过新的stream收发消息
+func (this *BackSession) SetStream(stream *ipc.Stream) {
+	if this.IsClosed() {
+		return
+	}
+
+	this.streamMutex.Lock()
+	defer this.streamMutex.Unlock()
+
+	if this.stream != nil {
+		//通知原connector之后的消息不再发送给它，之前的消息都已发送
+		this.stream.SendControl([]uint64{this.sessionId}, &ipc.Res{
+			Control: ipc.ControlType_CONTROL_MOVED,
+		})
+		this.stream.RemoveSession(this)
+	}
+	this.stream = stream
+	stream.AddSession(this)
+}
+
 func (this *BackSession) IsClosed() bool {
 	return atomic.LoadInt32(&this.closeFlag) == 1
 }
@@ -91,8 +309,12 @@ func (this *BackSession) Close() {
 
 		this.invokeCloseCallbacks()
 
-		this.stream.RemoveSession(this)
-		this.stream = nil
+		this.streamMutex.Lock()
+		if this.stream != nil {
+			this.stream.RemoveSession(this)
+			this.stream = nil
+		}
+		this.streamMutex.Unlock()
 
 		this.msgHandle = nil
 	}
@@ -160,8 +382,12 @@ func (this *BackSession) loop() {
 		select {
 		case msg, ok := <-this.recvChan:
 			if ok {
+				if msg.closed {
+					this.Close()
+					return
+				}
 				if this.msgHandle != nil {
-					this.msgHandle(this, msg)
+					this.handleMsg(msg)
 				}
 			} else {
 				return
@@ -171,3 +397,16 @@ func (this *BackSession) loop() {
 		}
 	}
 }
+
+func (this *BackSession) handleMsg(msg *backMsg) {
+	this.requestSeq = msg.requestSeq
+	if len(msg.data) >= 2 {
+		this.requestMsgId = binary.BigEndian.Uint16(msg.data[:2])
+	}
+	defer func() {
+		this.requestSeq = 0
+		this.requestMsgId = 0
+	}()
+
+	this.msgHandle(this, msg.data)
+}
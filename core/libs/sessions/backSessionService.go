@@ -51,3 +51,15 @@ func BackSessionLen() int {
 func CreateBackSessionId(serviceIdentify string, userSessionId uint64) string {
 	return serviceIdentify + "_" + cast.ToString(userSessionId)
 }
+
+// BackSessionQueueLen 所有BackSession中等待处理的消息数量
+func BackSessionQueueLen() int {
+	backSessionMutex.Lock()
+	defer backSessionMutex.Unlock()
+
+	num := 0
+	for _, session := range backSessions {
+		num += len(session.recvChan)
+	}
+	return num
+}
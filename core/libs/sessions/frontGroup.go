@@ -0,0 +1,113 @@
+package sessions
+
+import (
+	"sync"
+)
+
+var (
+	//分组中的Session，Session关闭时自动离开所有分组
+	frontGroups       = make(map[string]map[*FrontSession]struct{})
+	frontSessionGroup = make(map[*FrontSession]map[string]struct{})
+	frontGroupMutex   sync.RWMutex
+)
+
+const frontGroupCloseKey = "sessionGroup.LeaveAll"
+
+// JoinFrontGroup 加入分组(房间、公会、世界频道等)
+func JoinFrontGroup(group string, session *FrontSession) {
+	if session.IsClosed() {
+		return
+	}
+
+	frontGroupMutex.Lock()
+	members, ok := frontGroups[group]
+	if !ok {
+		members = make(map[*FrontSession]struct{})
+		frontGroups[group] = members
+	}
+	members[session] = struct{}{}
+
+	groups, ok := frontSessionGroup[session]
+	if !ok {
+		groups = make(map[string]struct{})
+		frontSessionGroup[session] = groups
+	}
+	groups[group] = struct{}{}
+	frontGroupMutex.Unlock()
+
+	if !ok {
+		session.AddCloseCallback(nil, frontGroupCloseKey, func() {
+			leaveAllFrontGroup(session)
+		})
+		//加入过程中Session已关闭
+		if session.IsClosed() {
+			leaveAllFrontGroup(session)
+		}
+	}
+}
+
+// LeaveFrontGroup 离开分组，分组中没有Session时删除分组
+func LeaveFrontGroup(group string, session *FrontSession) {
+	frontGroupMutex.Lock()
+	defer frontGroupMutex.Unlock()
+
+	removeGroupMember(group, session)
+	if groups, ok := frontSessionGroup[session]; ok {
+		delete(groups, group)
+	}
+}
+
+func leaveAllFrontGroup(session *FrontSession) {
+	frontGroupMutex.Lock()
+	defer frontGroupMutex.Unlock()
+
+	for group := range frontSessionGroup[session] {
+		removeGroupMember(group, session)
+	}
+	delete(frontSessionGroup, session)
+}
+
+func removeGroupMember(group string, session *FrontSession) {
+	members, ok := frontGroups[group]
+	if !ok {
+		return
+	}
+	delete(members, session)
+	if len(members) == 0 {
+		delete(frontGroups, group)
+	}
+}
+
+// FetchFrontGroup 遍历分组中的Session
+func FetchFrontGroup(group string, callback func(*FrontSession)) {
+	frontGroupMutex.RLock()
+	members := make([]*FrontSession, 0, len(frontGroups[group]))
+	for session := range frontGroups[group] {
+		members = append(members, session)
+	}
+	frontGroupMutex.RUnlock()
+
+	for _, session := range members {
+		callback(session)
+	}
+}
+
+// FrontGroupLen 分组中的Session数量
+func FrontGroupLen(group string) int {
+	frontGroupMutex.RLock()
+	defer frontGroupMutex.RUnlock()
+
+	return len(frontGroups[group])
+}
+
+// FrontSessionGroups Session所在的分组
+func FrontSessionGroups(session *FrontSession) []string {
+	frontGroupMutex.RLock()
+	defer frontGroupMutex.RUnlock()
+
+	groups := make([]string, 0, len(frontSessionGroup[session]))
+	for group := range frontSessionGroup[session] {
+		groups = append(groups, group)
+	}
+	return groups
+}
@@ -5,7 +5,17 @@ import (
 	"sync/atomic"
 	"time"
 
+	"github.com/yicaoyimuys/GoGameServer/core/libs/frame"
+	"github.com/yicaoyimuys/GoGameServer/core/libs/logger"
 	"github.com/yicaoyimuys/GoGameServer/core/libs/stack"
+	"go.uber.org/zap"
+)
+
+const (
+	//单次合并写入的最大消息数
+	sendBatchMax = 64
+	//Session关闭时等待发送队列写完的最长时间
+	sendFlushTimeout = 3 * time.Second
 )
 
 type Codec interface {
@@ -14,26 +24,46 @@ type Codec interface {
 	Close() error
 }
 
+// BatchCodec 支持将多条消息合并为一次写入
+type BatchCodec interface {
+	SendBatch([][]byte) error
+}
+
 type FrontSessionCreateHandle func(session *FrontSession)
 type FrontSessionReceiveMsgHandle func(session *FrontSession, msgBody []byte)
 
+// FrontSessionCloseHandle Session断开时调用，offline用于通知后端服务器下线，可延迟调用
+type FrontSessionCloseHandle func(session *FrontSession, offline func())
+
 type FrontSession struct {
 	id        uint64
 	codec     Codec
 	recvMutex sync.Mutex
 	sendMutex sync.RWMutex
 	recvChan  chan []byte
+	sendChan  chan []byte
+	taskChan  chan func()
+
+	sendDropCount int64
+	overflowFlag  int32
 
 	closeFlag          int32
 	closeChan          chan int
+	loopDone           chan struct{}
 	closeMutex         sync.Mutex
 	firstCloseCallback *closeCallback
 	lastCloseCallback  *closeCallback
 
 	msgHandle func(session *FrontSession, msgBody []byte)
 
-	pingTime    int64
-	ipcServices sync.Map
+	pingTime        int64
+	serverPingTime  int64
+	rtt             int64
+	remoteAddr      atomic.Value
+	userId          uint64
+	ipcServices     sync.Map
+	serviceIdentify atomic.Value
+	attrs           sync.Map
 }
 
 func NewFontSession(id uint64, codec Codec) *FrontSession {
@@ -41,15 +71,49 @@ func NewFontSession(id uint64, codec Codec) *FrontSession {
 		id:        id,
 		codec:     codec,
 		recvChan:  make(chan []byte, 100),
+		sendChan:  make(chan []byte, sendQueueSize),
+		taskChan:  make(chan func(), 100),
 		closeChan: make(chan int),
+		loopDone:  make(chan struct{}),
 		pingTime:  time.Now().Unix(),
+		rtt:       -1,
 	}
 	go session.loop()
+	go session.sendLoop()
 	return session
 }
 
 func (this *FrontSession) ID() uint64 {
-	return this.id
+	return atomic.LoadUint64(&this.id)
+}
+
+// ServiceIdentify 断线重连到其他connector后，后端服务器中的Session仍属于原connector
+func (this *FrontSession) ServiceIdentify() string {
+	value, _ := this.serviceIdentify.Load().(string)
+	return value
+}
+
+func (this *FrontSession) SetServiceIdentify(serviceIdentify string) {
+	this.serviceIdentify.Store(serviceIdentify)
+}
+
+// UserId 登录后绑定的用户ID，未登录时为0
+func (this *FrontSession) UserId() uint64 {
+	return atomic.LoadUint64(&this.userId)
+}
+
+func (this *FrontSession) SetUserId(userId uint64) {
+	atomic.StoreUint64(&this.userId, userId)
+}
+
+// RemoteAddr 客户端地址，负载均衡后为PROXY协议或X-Forwarded-For中的真实地址
+func (this *FrontSession) RemoteAddr() string {
+	value, _ := this.remoteAddr.Load().(string)
+	return value
+}
+
+func (this *FrontSession) SetRemoteAddr(remoteAddr string) {
+	this.remoteAddr.Store(remoteAddr)
 }
 
 func (this *FrontSession) IsClosed() bool {
@@ -64,16 +128,82 @@ func (this *FrontSession) GetIpcService(serviceName string) string {
 	return ""
 }
 
+func (this *FrontSession) SendDropCount() int64 {
+	return atomic.LoadInt64(&this.sendDropCount)
+}
+
 func (this *FrontSession) PingTime() int64 {
-	return this.pingTime
+	return atomic.LoadInt64(&this.pingTime)
+}
+
+// Rtt 最近一次服务器ping测得的往返时间(毫秒)，未测量时为-1
+func (this *FrontSession) Rtt() int64 {
+	return atomic.LoadInt64(&this.rtt)
+}
+
+func (this *FrontSession) SetRtt(rtt int64) {
+	atomic.StoreInt64(&this.rtt, rtt)
+}
+
+// SetServerPingTime 记录服务器ping的发送时间(毫秒)
+func (this *FrontSession) SetServerPingTime(pingTime int64) {
+	atomic.StoreInt64(&this.serverPingTime, pingTime)
+}
+
+// TakeServerPingTime 取出服务器ping的发送时间，每次ping只能取出一次，没有时返回0
+func (this *FrontSession) TakeServerPingTime() int64 {
+	return atomic.SwapInt64(&this.serverPingTime, 0)
 }
 
 func (this *FrontSession) SetIpcService(serviceName string, service string) {
 	this.ipcServices.Store(serviceName, service)
 }
 
+func (this *FrontSession) GetIpcServices() map[string]string {
+	services := make(map[string]string)
+	this.ipcServices.Range(func(key, value interface{}) bool {
+		services[key.(string)] = value.(string)
+		return true
+	})
+	return services
+}
+
+// Attr 后端服务器设置的Session属性
+func (this *FrontSession) Attr(key string) string {
+	value, _ := this.attrs.Load(key)
+	if value == nil {
+		return ""
+	}
+	return value.(string)
+}
+
+// SetAttr value为空时删除该属性
+func (this *FrontSession) SetAttr(key string, value string) {
+	if value == "" {
+		this.attrs.Delete(key)
+		return
+	}
+	this.attrs.Store(key, value)
+}
+
+func (this *FrontSession) ClearAttrs() {
+	this.attrs.Range(func(key, value interface{}) bool {
+		this.attrs.Delete(key)
+		return true
+	})
+}
+
+func (this *FrontSession) Attrs() map[string]string {
+	attrs := make(map[string]string)
+	this.attrs.Range(func(key, value interface{}) bool {
+		attrs[key.(string)] = value.(string)
+		return true
+	})
+	return attrs
+}
+
 func (this *FrontSession) UpdatePingTime() {
-	this.pingTime = time.Now().Unix()
+	atomic.StoreInt64(&this.pingTime, time.Now().Unix())
 }
 
 func (this *FrontSession) Close() {
@@ -84,10 +214,22 @@ func (this *FrontSession) Close() {
 		close(this.recvChan)
 		for _ = range this.recvChan {
 		}
+		close(this.taskChan)
+		for _ = range this.taskChan {
+		}
 		this.recvMutex.Unlock()
 
+		//发送队列中剩余的消息由sendLoop写完后再关闭连接
+		this.sendMutex.Lock()
+		close(this.sendChan)
+		this.sendMutex.Unlock()
+
 		this.invokeCloseCallbacks()
-		this.codec.Close()
+
+		//写阻塞时超时强制关闭
+		time.AfterFunc(sendFlushTimeout, func() {
+			this.codec.Close()
+		})
 
 		this.msgHandle = nil
 	}
@@ -107,15 +249,53 @@ func (this *FrontSession) Receive() ([]byte, error) {
 	return msg, err
 }
 
+// Post 在消息处理协程中执行task，与客户端消息按顺序处理，队列已满时返回ErrRecvQueueFull
+func (this *FrontSession) Post(task func()) error {
+	this.recvMutex.Lock()
+	defer this.recvMutex.Unlock()
+
+	if this.IsClosed() {
+		return ErrClosed
+	}
+
+	select {
+	case this.taskChan <- task:
+		return nil
+	default:
+		return ErrRecvQueueFull
+	}
+}
+
 func (this *FrontSession) Send(msg []byte) (err error) {
+	this.sendMutex.RLock()
+	defer this.sendMutex.RUnlock()
+
 	if this.IsClosed() {
 		return ErrClosed
 	}
 
-	this.sendMutex.Lock()
-	defer this.sendMutex.Unlock()
+	select {
+	case this.sendChan <- msg:
+		return nil
+	default:
+	}
 
-	return this.codec.Send(msg)
+	//发送队列已满，客户端接收过慢
+	if sendQueuePolicy == SendQueuePolicy_Disconnect {
+		if atomic.CompareAndSwapInt32(&this.overflowFlag, 0, 1) {
+			atomic.AddInt64(&sendDisconnectCount, 1)
+			logger.Warn("发送队列已满，断开连接", zap.Uint64("SessionId", this.ID()), zap.Int("QueueSize", cap(this.sendChan)))
+			//Send可能在持有Session列表锁时调用，需异步关闭
+			go func() {
+				this.Close()
+				this.codec.Close()
+			}()
+		}
+	} else {
+		atomic.AddInt64(&this.sendDropCount, 1)
+		atomic.AddInt64(&sendDropCount, 1)
+	}
+	return ErrSendQueueFull
 }
 
 func (this *FrontSession) AddCloseCallback(handler, key interface{}, callback func()) {
@@ -173,8 +353,14 @@ func (this *FrontSession) SetMsgHandle(msgHandle func(session *FrontSession, msg
 	this.msgHandle = msgHandle
 }
 
+// Handled 消息处理协程退出后关闭，之后不会再处理该Session的消息
+func (this *FrontSession) Handled() <-chan struct{} {
+	return this.loopDone
+}
+
 func (this *FrontSession) loop() {
 	defer stack.TryError()
+	defer close(this.loopDone)
 
 	for {
 		select {
@@ -186,8 +372,55 @@ func (this *FrontSession) loop() {
 			} else {
 				return
 			}
+		case task, ok := <-this.taskChan:
+			if ok {
+				task()
+			} else {
+				return
+			}
 		case <-this.closeChan:
 			return
 		}
 	}
 }
+
+func (this *FrontSession) sendLoop() {
+	defer stack.TryError()
+	defer this.codec.Close()
+
+	msgs := make([][]byte, 0, sendBatchMax)
+	for msg := range this.sendChan {
+		//合并队列中已积压的消息，一次写入
+		msgs = append(msgs, msg)
+		for len(msgs) < sendBatchMax && len(this.sendChan) > 0 {
+			msgs = append(msgs, <-this.sendChan)
+		}
+		if err := this.write(msgs); err != nil {
+			//连接已断开，丢弃之后的消息并关闭Session
+			logger.Debug("发送消息失败，断开连接", zap.Uint64("SessionId", this.ID()), zap.Error(err))
+			go this.Close()
+			for range this.sendChan {
+			}
+			return
+		}
+
+		for i := range msgs {
+			msgs[i] = nil
+		}
+		msgs = msgs[:0]
+	}
+}
+
+func (this *FrontSession) write(msgs [][]byte) error {
+	if batchCodec, ok := this.codec.(BatchCodec); ok {
+		return batchCodec.SendBatch(msgs)
+	}
+	for _, msg := range msgs {
+		err := this.codec.Send(msg)
+		//单条消息打包失败不影响其他消息
+		if err != nil && err != frame.ErrTooLarge && err != frame.ErrKeyExchange {
+			return err
+		}
+	}
+	return nil
+}
@@ -2,14 +2,27 @@ package sessions
 
 import (
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/yicaoyimuys/GoGameServer/core/libs/timer"
 )
 
+const (
+	SendQueuePolicy_Drop       = "drop"
+	SendQueuePolicy_Disconnect = "disconnect"
+)
+
 var (
 	frontSessions     = make(map[uint64]*FrontSession)
 	frontSessionMutex sync.Mutex
+
+	sendQueueSize       = 256
+	sendQueuePolicy     = SendQueuePolicy_Drop
+	sendDropCount       int64
+	sendDisconnectCount int64
+
+	pingOpened int32
 )
 
 func AddFrontSession(session *FrontSession) {
@@ -29,6 +42,16 @@ func RemoveFrontSession(key uint64) {
 	delete(frontSessions, key)
 }
 
+// RebindFrontSession 断线重连后新连接使用原Session的ID
+func RebindFrontSession(session *FrontSession, id uint64) {
+	frontSessionMutex.Lock()
+	defer frontSessionMutex.Unlock()
+
+	delete(frontSessions, session.ID())
+	atomic.StoreUint64(&session.id, id)
+	frontSessions[id] = session
+}
+
 func GetFrontSession(key uint64) *FrontSession {
 	frontSessionMutex.Lock()
 	defer frontSessionMutex.Unlock()
@@ -53,20 +76,22 @@ func FetchFrontSession(callback func(*FrontSession)) {
 	}
 }
 
-func FrontSessionOpenPing(overTimeSec int64) {
-	//2秒钟检测一次
-	timer.DoTimer(2*1000, func() {
-		frontSessionMutex.Lock()
+// FrontSessionOpenPing 开启心跳超时检测，overTimeSec秒内未收到消息的Session将被关闭，每intervalSec秒检测一次
+func FrontSessionOpenPing(overTimeSec int64, intervalSec int64) {
+	//多个前端Server共用Session列表，只需开启一次
+	if !atomic.CompareAndSwapInt32(&pingOpened, 0, 1) {
+		return
+	}
+
+	timer.DoTimer(uint32(intervalSec*1000), func() {
 		nowTime := time.Now().Unix()
 		closeSessions := []*FrontSession{}
-		for _, session := range frontSessions {
-			//超过15秒
+		for _, session := range FrontSessionList() {
 			cha := nowTime - session.PingTime()
 			if cha >= overTimeSec {
 				closeSessions = append(closeSessions, session)
 			}
 		}
-		frontSessionMutex.Unlock()
 
 		//关闭Session
 		for _, session := range closeSessions {
@@ -74,3 +99,32 @@ func FrontSessionOpenPing(overTimeSec int64) {
 		}
 	})
 }
+
+// FrontSessionList 当前所有Session的快照，遍历时不持有锁
+func FrontSessionList() []*FrontSession {
+	frontSessionMutex.Lock()
+	defer frontSessionMutex.Unlock()
+
+	list := make([]*FrontSession, 0, len(frontSessions))
+	for _, session := range frontSessions {
+		list = append(list, session)
+	}
+	return list
+}
+
+// FrontSessionSetSendQueue 设置发送队列长度及队列满时的处理方式，需在Session创建前调用
+func FrontSessionSetSendQueue(size int, policy string) {
+	if size > 0 {
+		sendQueueSize = size
+	}
+	if policy == SendQueuePolicy_Disconnect {
+		sendQueuePolicy = SendQueuePolicy_Disconnect
+	} else {
+		sendQueuePolicy = SendQueuePolicy_Drop
+	}
+}
+
+// FrontSessionSendQueueStats 发送队列溢出统计：丢弃的消息数、因此断开的连接数
+func FrontSessionSendQueueStats() (dropCount int64, disconnectCount int64) {
+	return atomic.LoadInt64(&sendDropCount), atomic.LoadInt64(&sendDisconnectCount)
+}
@@ -12,3 +12,5 @@ type closeCallback struct {
 }
 
 var ErrClosed = errors.New("session closed")
+var ErrSendQueueFull = errors.New("session send queue full")
+var ErrRecvQueueFull = errors.New("session receive queue full")
@@ -1,10 +1,17 @@
 package socket
 
 import (
+	"crypto/ed25519"
+	"crypto/tls"
+	"errors"
 	"net"
+	"time"
 
+	"github.com/yicaoyimuys/GoGameServer/core/libs/admission"
+	"github.com/yicaoyimuys/GoGameServer/core/libs/frame"
 	"github.com/yicaoyimuys/GoGameServer/core/libs/guid"
 	"github.com/yicaoyimuys/GoGameServer/core/libs/logger"
+	"github.com/yicaoyimuys/GoGameServer/core/libs/proxyproto"
 	"github.com/yicaoyimuys/GoGameServer/core/libs/sessions"
 	"github.com/yicaoyimuys/GoGameServer/core/libs/stack"
 	"go.uber.org/zap"
@@ -18,18 +25,65 @@ type Server struct {
 	port string
 	guid *guid.Guid
 
+	maxFrameSize      int
+	compressThreshold int
+	cryptoKey         string
+	signKey           ed25519.PrivateKey
+
+	useSSL bool
+	tslCrt string
+	tslKey string
+
+	admission     *admission.Admission
+	proxyProtocol bool
+
 	sessionCreateHandle     sessions.FrontSessionCreateHandle
 	sessionReceiveMsgHandle sessions.FrontSessionReceiveMsgHandle
 }
 
 func NewServer(port string, serviceId int) *Server {
 	server := &Server{
-		port: port,
-		guid: guid.NewGuid(uint16(serviceId)),
+		port:   port,
+		guid:   guid.NewGuid(uint16(serviceId)),
+		useSSL: false,
 	}
 	return server
 }
 
+func (this *Server) SetTLS(tslCrt string, tslKey string) {
+	this.useSSL = true
+	this.tslCrt = tslCrt
+	this.tslKey = tslKey
+}
+
+// SetCrypto 开启后客户端需先进行密钥交换，之后的消息全部加密，用于不能使用TLS的客户端
+// cryptoKey为服务器签名私钥文件，客户端使用对应公钥校验服务器
+func (this *Server) SetCrypto(cryptoKey string) {
+	this.cryptoKey = cryptoKey
+}
+
+// SetProxyProtocol 开启后连接必须带有PROXY协议头(v1或v2)，用于获取负载均衡后的客户端真实地址
+func (this *Server) SetProxyProtocol(proxyProtocol bool) {
+	this.proxyProtocol = proxyProtocol
+}
+
+// SetGuid 多个前端Server同时运行时需共用一个Guid，避免SessionID重复
+func (this *Server) SetGuid(guid *guid.Guid) {
+	this.guid = guid
+}
+
+func (this *Server) SetAdmission(admission *admission.Admission) {
+	this.admission = admission
+}
+
+func (this *Server) SetMaxFrameSize(maxFrameSize int) {
+	this.maxFrameSize = maxFrameSize
+}
+
+func (this *Server) SetCompressThreshold(compressThreshold int) {
+	this.compressThreshold = compressThreshold
+}
+
 func (this *Server) SetSessionCreateHandle(handle sessions.FrontSessionCreateHandle) {
 	this.sessionCreateHandle = handle
 }
@@ -38,44 +92,120 @@ func (this *Server) SetSessionReceiveMsgHandle(handle sessions.FrontSessionRecei
 	this.sessionReceiveMsgHandle = handle
 }
 
-func (this *Server) Start() {
+// Start 密钥、证书加载失败或端口监听失败时返回错误
+func (this *Server) Start() error {
 	logger.Info("Front Start Socket", zap.String("Port", this.port))
 
-	go func() {
-		defer stack.TryError()
+	if this.cryptoKey != "" {
+		signKey, err := frame.LoadSignKey(this.cryptoKey)
+		if err != nil {
+			return err
+		}
+		this.signKey = signKey
+	}
 
+	var cert tls.Certificate
+	if this.useSSL {
 		var err error
-		addr, err := net.ResolveTCPAddr(ServerNetworkType, "0.0.0.0:"+this.port)
-		stack.CheckError(err)
+		cert, err = tls.LoadX509KeyPair(this.tslCrt, this.tslKey)
+		if err != nil {
+			return err
+		}
+	}
+
+	addr, err := net.ResolveTCPAddr(ServerNetworkType, "0.0.0.0:"+this.port)
+	if err != nil {
+		return err
+	}
+	tcpListener, err := net.ListenTCP(ServerNetworkType, addr)
+	if err != nil {
+		return err
+	}
 
-		listener, err := net.ListenTCP(ServerNetworkType, addr)
-		stack.CheckError(err)
+	var listener net.Listener = tcpListener
+	//PROXY协议头在TLS握手之前
+	if this.proxyProtocol {
+		listener = proxyproto.NewListener(listener)
+	}
+	if this.useSSL {
+		listener = tls.NewListener(listener, &tls.Config{
+			Certificates: []tls.Certificate{cert},
+		})
+	}
 
+	go func() {
+		defer stack.TryError()
 		defer listener.Close()
+
 		logger.Info("Socket Waiting Client Connect...")
+		var delay time.Duration
 		for {
 			conn, err := listener.Accept()
-			stack.CheckError(err)
+			if err != nil {
+				if errors.Is(err, net.ErrClosed) {
+					return
+				}
+				//文件句柄耗尽等错误，等待后重试
+				if delay == 0 {
+					delay = 5 * time.Millisecond
+				} else if delay < time.Second {
+					delay *= 2
+				}
+				logger.Error("Socket Accept Error", zap.Error(err), zap.Duration("Retry", delay))
+				time.Sleep(delay)
+				continue
+			}
+			delay = 0
 
 			go this.handleConnect(conn)
 		}
 	}()
+	return nil
 }
 
-func (this *Server) StartPing() {
-	overTime := 15
-	sessions.FrontSessionOpenPing(int64(overTime))
-	logger.Info("Session超时时间设置", zap.Int("OverTime", overTime))
+func (this *Server) StartPing(overTime int, interval int) {
+	if overTime <= 0 {
+		overTime = 15
+	}
+	if interval <= 0 {
+		interval = 2
+	}
+	sessions.FrontSessionOpenPing(int64(overTime), int64(interval))
+	logger.Info("Session超时时间设置", zap.Int("OverTime", overTime), zap.Int("Interval", interval))
 }
 
 func (this *Server) handleConnect(conn net.Conn) {
 	//捕获异常
 	defer stack.TryError()
 
+	//开启PROXY协议时为客户端的真实地址
+	if this.proxyProtocol {
+		if err := proxyproto.CheckConn(conn); err != nil {
+			logger.Warn("PROXY协议头错误", zap.String("RemoteAddr", conn.RemoteAddr().String()), zap.Error(err))
+			conn.Close()
+			return
+		}
+	}
+	remoteAddr := conn.RemoteAddr().String()
+
+	//准入检测
+	if this.admission != nil {
+		release, err := this.admission.Accept(remoteAddr)
+		if err != nil {
+			logger.Warn("拒绝连接", zap.String("RemoteAddr", remoteAddr), zap.Error(err))
+			conn.Close()
+			return
+		}
+		defer release()
+	}
+
 	//Session创建
 	sessionId := this.guid.NewID()
-	sessionCodec := NewFrontCodec(conn)
+	protocol := frame.NewProtocol(this.maxFrameSize, this.compressThreshold)
+	protocol.SetCrypto(this.signKey)
+	sessionCodec := NewFrontCodec(conn, protocol)
 	session := sessions.NewFontSession(sessionId, sessionCodec)
+	session.SetRemoteAddr(remoteAddr)
 	this.addFontSession(session)
 }
 
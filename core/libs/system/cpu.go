@@ -0,0 +1,35 @@
+package system
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	//Linux中/proc的时间单位(USER_HZ)
+	clockTicks = 100
+)
+
+// ProcessCpuTime 本进程已使用的CPU时间(用户态+内核态)，不支持的系统返回0
+func ProcessCpuTime() time.Duration {
+	data, err := os.ReadFile("/proc/self/stat")
+	if err != nil {
+		return 0
+	}
+
+	//进程名中可能有空格，从最后一个')'之后开始解析
+	stat := string(data)
+	index := strings.LastIndexByte(stat, ')')
+	if index < 0 {
+		return 0
+	}
+	fields := strings.Fields(stat[index+1:])
+	if len(fields) < 13 {
+		return 0
+	}
+	utime, _ := strconv.ParseInt(fields[11], 10, 64)
+	stime, _ := strconv.ParseInt(fields[12], 10, 64)
+	return time.Duration(utime+stime) * time.Second / clockTicks
+}
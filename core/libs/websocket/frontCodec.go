@@ -1,23 +1,35 @@
 package websocket
 
 import (
-	"encoding/binary"
+	"errors"
+	"sync"
 
+	"github.com/yicaoyimuys/GoGameServer/core/libs/frame"
 	"github.com/yicaoyimuys/GoGameServer/core/libs/logger"
 	"github.com/yicaoyimuys/GoGameServer/core/libs/sessions"
+	"go.uber.org/zap"
 
 	"github.com/gorilla/websocket"
 )
 
-func NewFrontCodec(rw *websocket.Conn) sessions.Codec {
+var errMsgLen = errors.New("message length error")
+
+func NewFrontCodec(rw *websocket.Conn, protocol *frame.Protocol) sessions.Codec {
 	codec := &frontCodec{
-		rw: rw,
+		rw:       rw,
+		protocol: protocol,
 	}
+	//超出长度的消息由websocket库直接拒绝
+	rw.SetReadLimit(int64(protocol.ReadLimit()))
 	return codec
 }
 
 type frontCodec struct {
 	rw *websocket.Conn
+
+	protocol   *frame.Protocol
+	handshaked bool
+	sendMutex  sync.Mutex
 }
 
 func (this *frontCodec) Receive() ([]byte, error) {
@@ -26,29 +38,71 @@ func (this *frontCodec) Receive() ([]byte, error) {
 		return nil, err
 	}
 
-	if len(data) < 2 {
+	//握手只在第一条消息时检测
+	if !this.handshaked {
+		this.handshaked = true
+		if frame.IsHandshake(data) {
+			if err := this.handshake(data); err != nil {
+				return nil, err
+			}
+			return this.Receive()
+		}
+	}
+
+	headLen := this.protocol.HeadLen()
+	if len(data) < headLen {
 		logger.Error("消息长度不够")
-		return nil, err
+		return nil, errMsgLen
 	}
 
 	//消息长度
-	msgLen := binary.BigEndian.Uint16(data[:2])
+	msgLen, compressed, err := this.protocol.ParseHead(data[:headLen])
+	if err != nil {
+		logger.Error("消息长度超出限制", zap.Int("MsgLen", msgLen), zap.Int("MaxFrameSize", this.protocol.MaxSize()))
+		return nil, err
+	}
 	//消息内容
-	msgBody := data[2:]
+	msgBody := data[headLen:]
 	//长度检测
-	if len(msgBody) != int(msgLen) {
+	if len(msgBody) != msgLen {
 		logger.Error("消息长度不够")
+		return nil, errMsgLen
+	}
+
+	//解密、解压
+	msgBody, err = this.protocol.Unpack(msgBody, compressed)
+	if err != nil {
+		logger.Error("消息解析失败", zap.Error(err))
 		return nil, err
 	}
 
 	return msgBody, nil
 }
 
+func (this *frontCodec) handshake(data []byte) error {
+	//切换版本并回复确认，之后的消息都使用新版本
+	this.sendMutex.Lock()
+	defer this.sendMutex.Unlock()
+
+	response, err := this.protocol.AcceptHandshake(data)
+	if err != nil {
+		logger.Error("握手失败", zap.Binary("Data", data), zap.Error(err))
+		return err
+	}
+	return this.rw.WriteMessage(websocket.BinaryMessage, response)
+}
+
 func (this *frontCodec) Send(msg []byte) error {
-	msgLen := uint16(len(msg))
-	sendMsg := make([]byte, msgLen+2)
-	binary.BigEndian.PutUint16(sendMsg[:2], msgLen)
-	copy(sendMsg[2:], msg)
+	this.sendMutex.Lock()
+	defer this.sendMutex.Unlock()
+
+	sendMsg, err := this.protocol.Pack(msg)
+	if err == frame.ErrTooLarge {
+		logger.Error("发送消息长度超出限制", zap.Int("MsgLen", len(msg)), zap.Int("MaxFrameSize", this.protocol.MaxSize()))
+		return err
+	} else if err != nil {
+		return err
+	}
 
 	return this.rw.WriteMessage(websocket.BinaryMessage, sendMsg)
 }
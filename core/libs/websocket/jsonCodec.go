@@ -0,0 +1,61 @@
+package websocket
+
+import (
+	"sync"
+
+	"github.com/yicaoyimuys/GoGameServer/core/libs/logger"
+	"github.com/yicaoyimuys/GoGameServer/core/libs/protos"
+	"github.com/yicaoyimuys/GoGameServer/core/libs/sessions"
+	"go.uber.org/zap"
+
+	"github.com/gorilla/websocket"
+)
+
+// JSON格式比二进制大，读取长度按倍数放宽
+const jsonSizeRatio = 4
+
+// NewJsonCodec JSON调试子协议，使用文本帧，不支持握手协商的压缩和加密
+func NewJsonCodec(rw *websocket.Conn, maxFrameSize int) sessions.Codec {
+	codec := &jsonCodec{
+		rw: rw,
+	}
+	rw.SetReadLimit(int64(maxFrameSize * jsonSizeRatio))
+	return codec
+}
+
+type jsonCodec struct {
+	rw *websocket.Conn
+
+	sendMutex sync.Mutex
+}
+
+func (this *jsonCodec) Receive() ([]byte, error) {
+	_, data, err := this.rw.ReadMessage()
+	if err != nil {
+		return nil, err
+	}
+
+	msgBody, err := protos.JsonToProtoMsg(data)
+	if err != nil {
+		logger.Error("JSON消息解析失败", zap.ByteString("Data", data), zap.Error(err))
+		return nil, err
+	}
+	return msgBody, nil
+}
+
+func (this *jsonCodec) Send(msg []byte) error {
+	sendMsg, err := protos.ProtoMsgToJson(msg)
+	if err != nil {
+		logger.Error("JSON消息转换失败", zap.Error(err))
+		return err
+	}
+
+	this.sendMutex.Lock()
+	defer this.sendMutex.Unlock()
+
+	return this.rw.WriteMessage(websocket.TextMessage, sendMsg)
+}
+
+func (this *jsonCodec) Close() error {
+	return this.rw.Close()
+}
@@ -1,9 +1,15 @@
 package websocket
 
 import (
+	"net"
 	"net/http"
+	"net/url"
+	"strings"
 
+	"github.com/yicaoyimuys/GoGameServer/core/libs/admission"
+	"github.com/yicaoyimuys/GoGameServer/core/libs/frame"
 	"github.com/yicaoyimuys/GoGameServer/core/libs/guid"
+	"github.com/yicaoyimuys/GoGameServer/core/libs/iplist"
 	"github.com/yicaoyimuys/GoGameServer/core/libs/logger"
 	"github.com/yicaoyimuys/GoGameServer/core/libs/sessions"
 	"github.com/yicaoyimuys/GoGameServer/core/libs/stack"
@@ -12,22 +18,28 @@ import (
 	"github.com/gorilla/websocket"
 )
 
-var upgrader = websocket.Upgrader{
-	ReadBufferSize:  1024,
-	WriteBufferSize: 1024,
-	CheckOrigin: func(r *http.Request) bool {
-		return true
-	},
-}
+// JsonSubprotocol 浏览器调试使用的子协议，消息为JSON文本，例如: new WebSocket(url, "json")
+const JsonSubprotocol = "json"
 
 type Server struct {
 	port string
+	path string
 	guid *guid.Guid
 
+	upgrader     websocket.Upgrader
+	allowOrigins []string
+
+	maxFrameSize      int
+	compressThreshold int
+
 	useSSL bool
 	tslCrt string
 	tslKey string
 
+	admission      *admission.Admission
+	jsonDebug      bool
+	trustedProxies *iplist.IpList
+
 	sessionCreateHandle     sessions.FrontSessionCreateHandle
 	sessionReceiveMsgHandle sessions.FrontSessionReceiveMsgHandle
 }
@@ -35,18 +47,71 @@ type Server struct {
 func NewServer(port string, serviceId int) *Server {
 	server := &Server{
 		port:   port,
+		path:   "/",
 		guid:   guid.NewGuid(uint16(serviceId)),
 		useSSL: false,
 	}
+	server.upgrader = websocket.Upgrader{
+		ReadBufferSize:  1024,
+		WriteBufferSize: 1024,
+		CheckOrigin:     server.checkOrigin,
+	}
 	return server
 }
 
+// SetGuid 多个前端Server同时运行时需共用一个Guid，避免SessionID重复
+func (this *Server) SetGuid(guid *guid.Guid) {
+	this.guid = guid
+}
+
+// SetPath 设置WebSocket的请求路径，默认为/
+func (this *Server) SetPath(path string) {
+	if path == "" {
+		return
+	}
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	this.path = path
+}
+
+// SetAllowOrigins 设置允许的Origin，为空时只允许同源请求，*为允许所有
+func (this *Server) SetAllowOrigins(allowOrigins []string) {
+	this.allowOrigins = allowOrigins
+}
+
+// SetTrustedProxies 来自这些地址的请求使用X-Forwarded-For中的客户端地址
+func (this *Server) SetTrustedProxies(trustedProxies []string) {
+	ipList, invalid := iplist.NewIpList(trustedProxies)
+	for _, item := range invalid {
+		logger.Error("TrustedProxies格式错误", zap.String("Item", item))
+	}
+	this.trustedProxies = ipList
+}
+
 func (this *Server) SetTLS(tslCrt string, tslKey string) {
 	this.useSSL = true
 	this.tslCrt = tslCrt
 	this.tslKey = tslKey
 }
 
+func (this *Server) SetAdmission(admission *admission.Admission) {
+	this.admission = admission
+}
+
+// SetJsonDebug 允许客户端使用JSON调试子协议，正式环境不要开启
+func (this *Server) SetJsonDebug(jsonDebug bool) {
+	this.jsonDebug = jsonDebug
+}
+
+func (this *Server) SetMaxFrameSize(maxFrameSize int) {
+	this.maxFrameSize = maxFrameSize
+}
+
+func (this *Server) SetCompressThreshold(compressThreshold int) {
+	this.compressThreshold = compressThreshold
+}
+
 func (this *Server) SetSessionCreateHandle(handle sessions.FrontSessionCreateHandle) {
 	this.sessionCreateHandle = handle
 }
@@ -59,25 +124,56 @@ func (this *Server) Start() {
 	logger.Info("Front Start WebSocket", zap.String("Port", this.port))
 
 	go func() {
-		http.HandleFunc("/", this.wsHandler)
+		mux := http.NewServeMux()
+		mux.HandleFunc(this.path, this.wsHandler)
+		httpServer := &http.Server{
+			Addr:    "0.0.0.0:" + this.port,
+			Handler: mux,
+		}
+
 		var err error
 		if this.useSSL {
-			err = http.ListenAndServeTLS("0.0.0.0:"+this.port, this.tslCrt, this.tslKey, nil)
+			err = httpServer.ListenAndServeTLS(this.tslCrt, this.tslKey)
 		} else {
-			err = http.ListenAndServe("0.0.0.0:"+this.port, nil)
+			err = httpServer.ListenAndServe()
 		}
 		stack.CheckError(err)
 	}()
 }
 
-func (this *Server) StartPing() {
-	overTime := 15
-	sessions.FrontSessionOpenPing(int64(overTime))
-	logger.Info("Session超时时间设置", zap.Int("OverTime", overTime))
+func (this *Server) StartPing(overTime int, interval int) {
+	if overTime <= 0 {
+		overTime = 15
+	}
+	if interval <= 0 {
+		interval = 2
+	}
+	sessions.FrontSessionOpenPing(int64(overTime), int64(interval))
+	logger.Info("Session超时时间设置", zap.Int("OverTime", overTime), zap.Int("Interval", interval))
 }
 
 func (this *Server) wsHandler(w http.ResponseWriter, r *http.Request) {
-	conn, err := upgrader.Upgrade(w, r, nil)
+	remoteAddr := this.getRemoteAddr(r)
+
+	//准入检测，在升级协议之前拒绝
+	if this.admission != nil {
+		release, err := this.admission.Accept(remoteAddr)
+		if err != nil {
+			logger.Warn("拒绝连接", zap.String("RemoteAddr", remoteAddr), zap.Error(err))
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+		defer release()
+	}
+
+	//客户端请求JSON调试子协议
+	var responseHeader http.Header
+	useJson := this.jsonDebug && isJsonSubprotocol(r)
+	if useJson {
+		responseHeader = http.Header{"Sec-Websocket-Protocol": {JsonSubprotocol}}
+	}
+
+	conn, err := this.upgrader.Upgrade(w, r, responseHeader)
 	if err != nil {
 		return
 	}
@@ -87,11 +183,71 @@ func (this *Server) wsHandler(w http.ResponseWriter, r *http.Request) {
 
 	//Session创建
 	sessionId := this.guid.NewID()
-	sessionCodec := NewFrontCodec(conn)
+	protocol := frame.NewProtocol(this.maxFrameSize, this.compressThreshold)
+	var sessionCodec sessions.Codec
+	if useJson {
+		sessionCodec = NewJsonCodec(conn, protocol.MaxSize())
+	} else {
+		sessionCodec = NewFrontCodec(conn, protocol)
+	}
 	session := sessions.NewFontSession(sessionId, sessionCodec)
+	session.SetRemoteAddr(remoteAddr)
 	this.addFontSession(session)
 }
 
+// 请求来自可信代理时，从右往左取X-Forwarded-For中第一个非可信代理的地址
+func (this *Server) getRemoteAddr(r *http.Request) string {
+	if this.trustedProxies == nil || this.trustedProxies.Len() == 0 {
+		return r.RemoteAddr
+	}
+	if !this.trustedProxies.Contains(iplist.ParseIp(r.RemoteAddr)) {
+		return r.RemoteAddr
+	}
+
+	forwardedIps := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+	for i := len(forwardedIps) - 1; i >= 0; i-- {
+		ip := net.ParseIP(strings.TrimSpace(forwardedIps[i]))
+		if ip == nil {
+			break
+		}
+		if !this.trustedProxies.Contains(ip) {
+			//X-Forwarded-For中没有端口
+			return net.JoinHostPort(ip.String(), "0")
+		}
+	}
+	return r.RemoteAddr
+}
+
+func (this *Server) checkOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	//非浏览器客户端不带Origin
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	if len(this.allowOrigins) == 0 {
+		return strings.EqualFold(u.Host, r.Host)
+	}
+	for _, allowOrigin := range this.allowOrigins {
+		if allowOrigin == "*" || strings.EqualFold(allowOrigin, origin) || strings.EqualFold(allowOrigin, u.Host) {
+			return true
+		}
+	}
+	return false
+}
+
+func isJsonSubprotocol(r *http.Request) bool {
+	for _, subprotocol := range websocket.Subprotocols(r) {
+		if subprotocol == JsonSubprotocol {
+			return true
+		}
+	}
+	return false
+}
+
 func (this *Server) addFontSession(session *sessions.FrontSession) {
 	sessions.AddFrontSession(session)
 	if this.sessionCreateHandle != nil {
@@ -0,0 +1,44 @@
+package websocket
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetRemoteAddr(t *testing.T) {
+	tests := []struct {
+		name           string
+		trustedProxies []string
+		remoteAddr     string
+		forwardedFor   []string
+		want           string
+	}{
+		{name: "no trusted proxies", remoteAddr: "10.0.0.1:5000", forwardedFor: []string{"1.2.3.4"}, want: "10.0.0.1:5000"},
+		{name: "untrusted peer", trustedProxies: []string{"10.0.0.0/8"}, remoteAddr: "8.8.8.8:5000", forwardedFor: []string{"1.2.3.4"}, want: "8.8.8.8:5000"},
+		{name: "trusted peer", trustedProxies: []string{"10.0.0.0/8"}, remoteAddr: "10.0.0.1:5000", forwardedFor: []string{"1.2.3.4"}, want: "1.2.3.4:0"},
+		{name: "trusted peer without header", trustedProxies: []string{"10.0.0.0/8"}, remoteAddr: "10.0.0.1:5000", want: "10.0.0.1:5000"},
+		{name: "chain", trustedProxies: []string{"10.0.0.0/8"}, remoteAddr: "10.0.0.1:5000", forwardedFor: []string{"1.2.3.4, 5.6.7.8, 10.0.0.2"}, want: "5.6.7.8:0"},
+		{name: "chain in multiple headers", trustedProxies: []string{"10.0.0.0/8"}, remoteAddr: "10.0.0.1:5000", forwardedFor: []string{"1.2.3.4", "5.6.7.8", "10.0.0.2"}, want: "5.6.7.8:0"},
+		{name: "spoofed left entry", trustedProxies: []string{"10.0.0.0/8"}, remoteAddr: "10.0.0.1:5000", forwardedFor: []string{"10.0.0.9, 5.6.7.8"}, want: "5.6.7.8:0"},
+		{name: "all trusted", trustedProxies: []string{"10.0.0.0/8"}, remoteAddr: "10.0.0.1:5000", forwardedFor: []string{"10.0.0.2, 10.0.0.3"}, want: "10.0.0.1:5000"},
+		{name: "invalid entry", trustedProxies: []string{"10.0.0.0/8"}, remoteAddr: "10.0.0.1:5000", forwardedFor: []string{"1.2.3.4, unknown"}, want: "10.0.0.1:5000"},
+		{name: "single ip", trustedProxies: []string{"10.0.0.1"}, remoteAddr: "10.0.0.1:5000", forwardedFor: []string{"1.2.3.4, 10.0.0.2"}, want: "10.0.0.2:0"},
+		{name: "ipv6", trustedProxies: []string{"fd00::/8"}, remoteAddr: "[fd00::1]:5000", forwardedFor: []string{"2001:db8::1, fd00::2"}, want: "[2001:db8::1]:0"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := NewServer("0", 1)
+			server.SetTrustedProxies(test.trustedProxies)
+
+			r := httptest.NewRequest("GET", "/", nil)
+			r.RemoteAddr = test.remoteAddr
+			for _, value := range test.forwardedFor {
+				r.Header.Add("X-Forwarded-For", value)
+			}
+			if remoteAddr := server.getRemoteAddr(r); remoteAddr != test.want {
+				t.Fatalf("getRemoteAddr = %s, want %s", remoteAddr, test.want)
+			}
+		})
+	}
+}
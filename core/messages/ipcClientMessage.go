@@ -1,6 +1,8 @@
 package messages
 
 import (
+	"sync"
+
 	. "github.com/yicaoyimuys/GoGameServer/core/libs"
 	"github.com/yicaoyimuys/GoGameServer/core/libs/grpc/ipc"
 	"github.com/yicaoyimuys/GoGameServer/core/libs/protos"
@@ -8,22 +10,239 @@ import (
 	"go.uber.org/zap"
 )
 
+// FrontSessionMissHandle 消息接收者的FrontSession不存在时调用，返回true表示已处理，userSessionId为0时表示广播消息
+type FrontSessionMissHandle func(userSessionId uint64, data []byte, reliable bool) bool
+
+// FrontSessionReliableHandle 发送需客户端确认的消息
+type FrontSessionReliableHandle func(session *sessions.FrontSession, data []byte)
+
+// ResponsePackHandle 将请求的回复与请求序号打包为发送给客户端的消息
+type ResponsePackHandle func(requestSeq uint32, data []byte) []byte
+
+// FrontSessionKickHandle 后端服务器要求断开客户端连接
+type FrontSessionKickHandle func(session *sessions.FrontSession, reason string, data []byte)
+
+// FrontSessionPinHandle 后端服务器指定Session之后使用的服务器
+type FrontSessionPinHandle func(session *sessions.FrontSession, serviceName string, service string)
+
+// FrontSessionForwardHandle 后端服务器代替客户端发送消息给其他后端服务器
+type FrontSessionForwardHandle func(session *sessions.FrontSession, serviceName string, service string, data []byte)
+
+// FrontSessionMovedHandle 后端服务器通知Session已断线重连到其他connector
+type FrontSessionMovedHandle func(userSessionId uint64)
+
+var (
+	frontSessionMissHandle     FrontSessionMissHandle
+	frontSessionMovedHandle    FrontSessionMovedHandle
+	frontSessionReliableHandle FrontSessionReliableHandle
+	responsePackHandle         ResponsePackHandle
+	frontSessionKickHandle     FrontSessionKickHandle
+	frontSessionPinHandle      FrontSessionPinHandle
+	frontSessionForwardHandle  FrontSessionForwardHandle
+
+	//暂存消息的Session，断线重连补发消息期间使用
+	holdMsgs  = make(map[uint64][]*ipc.Res)
+	holdMutex sync.Mutex
+)
+
+// SetFrontSessionMissHandle 用于断线重连期间缓存发送给客户端的消息
+func SetFrontSessionMissHandle(handle FrontSessionMissHandle) {
+	frontSessionMissHandle = handle
+}
+
+// SetFrontSessionMovedHandle 未设置时忽略该通知
+func SetFrontSessionMovedHandle(handle FrontSessionMovedHandle) {
+	frontSessionMovedHandle = handle
+}
+
+// HoldFrontSession 之后发送给这些Session的消息暂存，直到ReleaseFrontSession
+func HoldFrontSession(userSessionIds ...uint64) {
+	holdMutex.Lock()
+	defer holdMutex.Unlock()
+
+	for _, userSessionId := range userSessionIds {
+		if _, ok := holdMsgs[userSessionId]; !ok {
+			holdMsgs[userSessionId] = []*ipc.Res{}
+		}
+	}
+}
+
+// ReleaseFrontSession 先调用before(补发断线期间的消息)，再按顺序发送暂存的消息，Session不存在时丢弃
+func ReleaseFrontSession(before func(), userSessionIds ...uint64) {
+	holdMutex.Lock()
+	defer holdMutex.Unlock()
+
+	if before != nil {
+		before()
+	}
+	for _, userSessionId := range userSessionIds {
+		msgs := holdMsgs[userSessionId]
+		delete(holdMsgs, userSessionId)
+
+		clientSession := sessions.GetFrontSession(userSessionId)
+		if clientSession == nil {
+			continue
+		}
+		for _, msg := range msgs {
+			sendToFrontSession(clientSession, msg)
+		}
+	}
+}
+
+// 需暂存时返回true
+func holdFrontSessionMsg(userSessionId uint64, msg *ipc.Res) bool {
+	holdMutex.Lock()
+	defer holdMutex.Unlock()
+
+	msgs, ok := holdMsgs[userSessionId]
+	if ok {
+		holdMsgs[userSessionId] = append(msgs, msg)
+	}
+	return ok
+}
+
+// SetFrontSessionReliableHandle 未设置时需确认的消息按普通消息发送
+func SetFrontSessionReliableHandle(handle FrontSessionReliableHandle) {
+	frontSessionReliableHandle = handle
+}
+
+// SetResponsePackHandle 未设置时请求的回复不带序号发送
+func SetResponsePackHandle(handle ResponsePackHandle) {
+	responsePackHandle = handle
+}
+
+// SetFrontSessionKickHandle 未设置时发送data后直接断开连接
+func SetFrontSessionKickHandle(handle FrontSessionKickHandle) {
+	frontSessionKickHandle = handle
+}
+
+// SetFrontSessionPinHandle 在Session记录指定的服务器之后调用
+func SetFrontSessionPinHandle(handle FrontSessionPinHandle) {
+	frontSessionPinHandle = handle
+}
+
+// SetFrontSessionForwardHandle 未设置时忽略转发的消息
+func SetFrontSessionForwardHandle(handle FrontSessionForwardHandle) {
+	frontSessionForwardHandle = handle
+}
+
 func IpcClientReceive(stream ipc.Ipc_TransferClient, msg *ipc.Res) {
+	if msg.Control != ipc.ControlType_CONTROL_NONE {
+		dealControl(msg)
+		return
+	}
+
+	if msg.RequestSeq != 0 && responsePackHandle != nil {
+		msg.Data = responsePackHandle(msg.RequestSeq, msg.Data)
+	}
+
 	if msg.UserSessionIds == nil {
 		//发送给所有人
 		sessions.FetchFrontSession(func(clientSession *sessions.FrontSession) {
-			clientSession.Send(msg.Data)
+			if !holdFrontSessionMsg(clientSession.ID(), msg) {
+				sendToFrontSession(clientSession, msg)
+			}
 		})
+		if frontSessionMissHandle != nil {
+			frontSessionMissHandle(0, msg.Data, msg.Reliable)
+		}
 	} else {
 		//发送给多个人
 		for _, userSessionId := range msg.UserSessionIds {
+			if holdFrontSessionMsg(userSessionId, msg) {
+				continue
+			}
 			clientSession := sessions.GetFrontSession(userSessionId)
 			if clientSession != nil {
-				clientSession.Send(msg.Data)
-			} else {
+				sendToFrontSession(clientSession, msg)
+			} else if frontSessionMissHandle == nil || !frontSessionMissHandle(userSessionId, msg.Data, msg.Reliable) {
 				msgId := protos.UnmarshalProtoId(msg.Data)
 				WARN("FrontSession No Exists", zap.Uint16("MsgId", msgId))
 			}
 		}
 	}
 }
+
+func sendToFrontSession(clientSession *sessions.FrontSession, msg *ipc.Res) {
+	if msg.Reliable && frontSessionReliableHandle != nil {
+		frontSessionReliableHandle(clientSession, msg.Data)
+	} else {
+		clientSession.Send(msg.Data)
+	}
+}
+
+func dealControl(msg *ipc.Res) {
+	//发送给分组
+	if msg.Control == ipc.ControlType_CONTROL_GROUP_PUBLISH {
+		sessions.FetchFrontGroup(msg.Group, func(clientSession *sessions.FrontSession) {
+			clientSession.Send(msg.Data)
+		})
+		return
+	}
+
+	//Session已断开，不在本connector中
+	if msg.Control == ipc.ControlType_CONTROL_MOVED {
+		if frontSessionMovedHandle != nil {
+			for _, userSessionId := range msg.UserSessionIds {
+				frontSessionMovedHandle(userSessionId)
+			}
+		}
+		return
+	}
+
+	for _, userSessionId := range msg.UserSessionIds {
+		clientSession := sessions.GetFrontSession(userSessionId)
+		if clientSession == nil {
+			continue
+		}
+
+		switch msg.Control {
+		case ipc.ControlType_CONTROL_GROUP_JOIN:
+			sessions.JoinFrontGroup(msg.Group, clientSession)
+		case ipc.ControlType_CONTROL_GROUP_LEAVE:
+			sessions.LeaveFrontGroup(msg.Group, clientSession)
+		case ipc.ControlType_CONTROL_KICK:
+			kickFrontSession(clientSession, msg.Reason, msg.Data)
+		case ipc.ControlType_CONTROL_BIND_USER:
+			//登录服务器在回复前发送，stream按接收顺序处理，客户端收到登录回复时已绑定
+			clientSession.SetUserId(msg.UserId)
+		case ipc.ControlType_CONTROL_SET_ATTRS:
+			for key, value := range msg.Attrs {
+				clientSession.SetAttr(key, value)
+			}
+		case ipc.ControlType_CONTROL_CLEAR_ATTRS:
+			clientSession.ClearAttrs()
+		case ipc.ControlType_CONTROL_PIN:
+			clientSession.SetIpcService(msg.ServiceName, msg.Service)
+			if frontSessionPinHandle != nil {
+				frontSessionPinHandle(clientSession, msg.ServiceName, msg.Service)
+			}
+		case ipc.ControlType_CONTROL_FORWARD:
+			if frontSessionForwardHandle == nil {
+				WARN("未设置转发处理", zap.String("ServiceName", msg.ServiceName))
+				return
+			}
+			//转发需访问Redis并发送给其他服务器，在Session的消息处理协程中执行，不阻塞stream的接收
+			err := clientSession.Post(func() {
+				frontSessionForwardHandle(clientSession, msg.ServiceName, msg.Service, msg.Data)
+			})
+			if err != nil {
+				WARN("转发消息失败", zap.Uint64("SessionId", clientSession.ID()), zap.String("ServiceName", msg.ServiceName), zap.Error(err))
+			}
+		default:
+			WARN("未知的控制消息", zap.Int32("Control", int32(msg.Control)))
+			return
+		}
+	}
+}
+
+func kickFrontSession(clientSession *sessions.FrontSession, reason string, data []byte) {
+	if frontSessionKickHandle != nil {
+		frontSessionKickHandle(clientSession, reason, data)
+		return
+	}
+	if data != nil {
+		clientSession.Send(data)
+	}
+	clientSession.Close()
+}
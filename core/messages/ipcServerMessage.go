@@ -14,12 +14,40 @@ func IpcServerReceive(stream *ipc.Stream, msg *ipc.Req) {
 	//获取Session
 	id := sessions.CreateBackSessionId(msg.ServiceIdentify, msg.UserSessionId)
 	session := sessions.GetBackSession(id)
+
+	//客户端已断开，断线重连到其他connector后原connector的通知无效
+	if msg.Closed {
+		if session != nil && session.IsStream(stream) {
+			session.ReceiveClose()
+		}
+		return
+	}
+
 	if session == nil {
+		if len(msgBody) == 0 {
+			return
+		}
 		session = sessions.NewBackSession(id, msg.UserSessionId, stream)
 		session.SetMsgHandle(dealMessage)
 		sessions.SetBackSession(session)
+	} else if !session.IsStream(stream) {
+		//断线重连到其他connector，之后的消息通过新的stream发送
+		session.SetStream(stream)
+	}
+	session.SetRemoteAddr(msg.RemoteAddr)
+	//登录后connector随消息转发绑定的用户ID
+	if msg.UserId != 0 && msg.UserId != session.UserID() {
+		session.SetUserId(msg.UserId)
+	}
+
+	//空消息仅用于断线重连后绑定stream
+	if len(msgBody) == 0 {
+		return
+	}
+	err := session.Receive(msgBody, msg.RequestSeq)
+	if err == sessions.ErrRecvQueueFull {
+		WARN("消息处理过慢，断开连接", zap.Uint64("UserSessionId", msg.UserSessionId), zap.Uint64("UserId", session.UserID()))
 	}
-	session.Receive(msgBody)
 }
 
 func dealMessage(session *sessions.BackSession, msgBody []byte) {
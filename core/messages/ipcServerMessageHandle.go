@@ -6,17 +6,41 @@ import (
 	"google.golang.org/protobuf/proto"
 )
 
-type ipcServerMsgHandle func(clientSession *sessions.BackSession, msgData proto.Message)
+type IpcServerMsgHandle func(clientSession *sessions.BackSession, msgData proto.Message)
+
+// IpcServerMiddleware 消息处理中间件，不调用next时消息不再向后处理
+type IpcServerMiddleware func(next IpcServerMsgHandle) IpcServerMsgHandle
+
+type ipcServerHandleInfo struct {
+	handle      IpcServerMsgHandle
+	middlewares []IpcServerMiddleware
+}
 
 var (
-	backHandles = make(map[uint16]ipcServerMsgHandle)
+	backHandleInfos   = make(map[uint16]*ipcServerHandleInfo)
+	backHandles       = make(map[uint16]IpcServerMsgHandle)
+	globalMiddlewares []IpcServerMiddleware
 )
 
-func RegisterIpcServerHandle(msgId uint16, handle ipcServerMsgHandle) {
-	backHandles[msgId] = handle
+// UseIpcServerMiddleware 添加对所有消息生效的中间件，按添加顺序执行，先于单个消息的中间件
+func UseIpcServerMiddleware(middlewares ...IpcServerMiddleware) {
+	globalMiddlewares = append(globalMiddlewares, middlewares...)
+	for msgId, info := range backHandleInfos {
+		backHandles[msgId] = buildIpcServerHandle(info)
+	}
 }
 
-func GetIpcServerHandle(msgId uint16) ipcServerMsgHandle {
+// RegisterIpcServerHandle middlewares为只对该消息生效的中间件，按顺序执行
+func RegisterIpcServerHandle(msgId uint16, handle IpcServerMsgHandle, middlewares ...IpcServerMiddleware) {
+	info := &ipcServerHandleInfo{
+		handle:      handle,
+		middlewares: middlewares,
+	}
+	backHandleInfos[msgId] = info
+	backHandles[msgId] = buildIpcServerHandle(info)
+}
+
+func GetIpcServerHandle(msgId uint16) IpcServerMsgHandle {
 	handle, ok := backHandles[msgId]
 	if ok {
 		return handle
@@ -24,3 +48,14 @@ func GetIpcServerHandle(msgId uint16) ipcServerMsgHandle {
 		return nil
 	}
 }
+
+func buildIpcServerHandle(info *ipcServerHandleInfo) IpcServerMsgHandle {
+	handle := info.handle
+	for i := len(info.middlewares) - 1; i >= 0; i-- {
+		handle = info.middlewares[i](handle)
+	}
+	for i := len(globalMiddlewares) - 1; i >= 0; i-- {
+		handle = globalMiddlewares[i](handle)
+	}
+	return handle
+}
@@ -0,0 +1,136 @@
+package messages
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	. "github.com/yicaoyimuys/GoGameServer/core/libs"
+	"github.com/yicaoyimuys/GoGameServer/core/libs/ratelimit"
+	"github.com/yicaoyimuys/GoGameServer/core/libs/sessions"
+	"github.com/yicaoyimuys/GoGameServer/core/libs/stack"
+	"go.uber.org/zap"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// ErrNotLogin Session未绑定用户时发送需登录的消息
+var ErrNotLogin = errors.New("messages: not login")
+
+// RequireLogin 需登录后才能处理的消息，Session未绑定用户时交给SetIpcServerErrorHandle设置的处理
+// 用户ID由登录服务器BindUser绑定，之后connector随每条消息转发给所有后端服务器
+func RequireLogin() IpcServerMiddleware {
+	return func(next IpcServerMsgHandle) IpcServerMsgHandle {
+		return func(clientSession *sessions.BackSession, msgData proto.Message) {
+			if !clientSession.IsLogin() {
+				ipcServerErrorHandle(clientSession, ErrNotLogin)
+				return
+			}
+			next(clientSession, msgData)
+		}
+	}
+}
+
+// RecoverMiddleware 捕获消息处理中的异常，onPanic可用于回复客户端错误，Session继续处理之后的消息
+func RecoverMiddleware(onPanic func(clientSession *sessions.BackSession, recover interface{})) IpcServerMiddleware {
+	return func(next IpcServerMsgHandle) IpcServerMsgHandle {
+		return func(clientSession *sessions.BackSession, msgData proto.Message) {
+			defer func() {
+				if x := recover(); x != nil {
+					ERR("消息处理异常", zap.Uint16("MsgId", clientSession.RequestMsgId()), zap.Any("Recover", x))
+					stack.PrintPanicStack()
+					if onPanic != nil {
+						onPanic(clientSession, x)
+					}
+				}
+			}()
+			next(clientSession, msgData)
+		}
+	}
+}
+
+// TimingMiddleware 处理时间超过slowTime时输出警告日志
+func TimingMiddleware(slowTime time.Duration) IpcServerMiddleware {
+	return func(next IpcServerMsgHandle) IpcServerMsgHandle {
+		return func(clientSession *sessions.BackSession, msgData proto.Message) {
+			startTime := time.Now()
+			next(clientSession, msgData)
+
+			useTime := time.Since(startTime)
+			if useTime >= slowTime {
+				WARN("消息处理过慢", zap.Uint16("MsgId", clientSession.RequestMsgId()), zap.Uint64("UserId", clientSession.UserID()), zap.Duration("UseTime", useTime))
+			}
+		}
+	}
+}
+
+// LogMiddleware 输出收到的消息
+func LogMiddleware() IpcServerMiddleware {
+	return func(next IpcServerMsgHandle) IpcServerMsgHandle {
+		return func(clientSession *sessions.BackSession, msgData proto.Message) {
+			DEBUG("收到消息", zap.Uint16("MsgId", clientSession.RequestMsgId()), zap.Uint64("UserId", clientSession.UserID()), zap.String("RemoteAddr", clientSession.RemoteAddr()))
+			next(clientSession, msgData)
+		}
+	}
+}
+
+// ValidateMiddleware check返回false时不再处理，由onInvalid回复客户端
+func ValidateMiddleware(check func(msgData proto.Message) bool, onInvalid func(clientSession *sessions.BackSession)) IpcServerMiddleware {
+	return func(next IpcServerMsgHandle) IpcServerMsgHandle {
+		return func(clientSession *sessions.BackSession, msgData proto.Message) {
+			if !check(msgData) {
+				if onInvalid != nil {
+					onInvalid(clientSession)
+				}
+				return
+			}
+			next(clientSession, msgData)
+		}
+	}
+}
+
+// RateLimitMiddleware 每个Session单独限流，rate为每秒允许的消息数，burst为允许的突发消息数
+// 超出时不再处理，由onLimit回复客户端；同一个中间件用于多个消息时共用限额
+func RateLimitMiddleware(rate float64, burst float64, onLimit func(clientSession *sessions.BackSession)) IpcServerMiddleware {
+	var (
+		buckets = make(map[string]*ratelimit.TokenBucket)
+		mutex   sync.Mutex
+	)
+	removeBucket := func(clientSession *sessions.BackSession) {
+		mutex.Lock()
+		delete(buckets, clientSession.ID())
+		mutex.Unlock()
+	}
+	getBucket := func(clientSession *sessions.BackSession) *ratelimit.TokenBucket {
+		mutex.Lock()
+		bucket, ok := buckets[clientSession.ID()]
+		if !ok {
+			bucket = ratelimit.NewTokenBucket(rate, burst)
+			buckets[clientSession.ID()] = bucket
+		}
+		mutex.Unlock()
+
+		if !ok {
+			clientSession.AddCloseCallback(&buckets, nil, func() {
+				removeBucket(clientSession)
+			})
+			//添加过程中Session已关闭
+			if clientSession.IsClosed() {
+				removeBucket(clientSession)
+			}
+		}
+		return bucket
+	}
+
+	return func(next IpcServerMsgHandle) IpcServerMsgHandle {
+		return func(clientSession *sessions.BackSession, msgData proto.Message) {
+			if !getBucket(clientSession).Allow() {
+				if onLimit != nil {
+					onLimit(clientSession)
+				}
+				return
+			}
+			next(clientSession, msgData)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package messages
+
+import (
+	"reflect"
+	"strconv"
+
+	. "github.com/yicaoyimuys/GoGameServer/core/libs"
+	"github.com/yicaoyimuys/GoGameServer/core/libs/protos"
+	"github.com/yicaoyimuys/GoGameServer/core/libs/sessions"
+	"go.uber.org/zap"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// Context 当前处理中消息的上下文，创建时取出请求序号，可在其他协程中或处理返回后回复
+type Context struct {
+	Session    *sessions.BackSession
+	requestSeq uint32
+	msgId      uint16
+}
+
+func newContext(clientSession *sessions.BackSession) *Context {
+	return &Context{
+		Session:    clientSession,
+		requestSeq: clientSession.RequestSeq(),
+		msgId:      clientSession.RequestMsgId(),
+	}
+}
+
+func (this *Context) UserID() uint64 {
+	return this.Session.UserID()
+}
+
+func (this *Context) MsgId() uint16 {
+	return this.msgId
+}
+
+func (this *Context) RequestSeq() uint32 {
+	return this.requestSeq
+}
+
+// Reply 回复该请求，客户端请求带有序号时回复中带上相同的序号
+func (this *Context) Reply(resp proto.Message) error {
+	return this.Session.SendResponse(protos.MarshalProtoMsg(resp), this.requestSeq)
+}
+
+type IpcServerErrorHandle func(clientSession *sessions.BackSession, err error)
+
+var (
+	ipcServerErrorHandle IpcServerErrorHandle = logIpcServerError
+)
+
+// SetIpcServerErrorHandle 设置RegisterIpcServerRequest中返回错误时的处理，一般为回复客户端错误码
+func SetIpcServerErrorHandle(handle IpcServerErrorHandle) {
+	ipcServerErrorHandle = handle
+}
+
+func logIpcServerError(clientSession *sessions.BackSession, err error) {
+	ERR("消息处理错误", zap.Uint16("MsgId", clientSession.RequestMsgId()), zap.Error(err))
+}
+
+// RegisterIpcServerRequest 注册请求-回复类型的消息处理，Req需与msgId对应的消息类型一致
+// 返回的Resp不为nil时自动回复客户端，返回error时由SetIpcServerErrorHandle设置的处理回复客户端
+func RegisterIpcServerRequest[Req proto.Message, Resp proto.Message](msgId uint16, handle func(ctx *Context, req Req) (Resp, error), middlewares ...IpcServerMiddleware) {
+	var req Req
+	if reqMsgId, ok := protos.MsgIDMap[reflect.TypeOf(req)]; !ok || reqMsgId != msgId {
+		panic("messages: request type " + reflect.TypeOf(req).String() + " does not match msgId " + strconv.Itoa(int(msgId)))
+	}
+	var resp Resp
+	if _, ok := protos.MsgIDMap[reflect.TypeOf(resp)]; !ok {
+		panic("messages: response type " + reflect.TypeOf(resp).String() + " is not registered")
+	}
+
+	RegisterIpcServerHandle(msgId, func(clientSession *sessions.BackSession, msgData proto.Message) {
+		ctx := newContext(clientSession)
+		resp, err := handle(ctx, msgData.(Req))
+		if err != nil {
+			ipcServerErrorHandle(clientSession, err)
+			return
+		}
+		if resp.ProtoReflect().IsValid() {
+			ctx.Reply(resp)
+		}
+	}, middlewares...)
+}
@@ -7,15 +7,20 @@ import (
 	beegoOrm "github.com/astaxie/beego/orm"
 	"github.com/yicaoyimuys/GoGameServer/core"
 	"github.com/yicaoyimuys/GoGameServer/core/config"
+	"github.com/yicaoyimuys/GoGameServer/core/consts"
 	. "github.com/yicaoyimuys/GoGameServer/core/libs"
+	"github.com/yicaoyimuys/GoGameServer/core/libs/admission"
 	"github.com/yicaoyimuys/GoGameServer/core/libs/common"
 	"github.com/yicaoyimuys/GoGameServer/core/libs/consul"
 	"github.com/yicaoyimuys/GoGameServer/core/libs/grpc/ipc"
+	"github.com/yicaoyimuys/GoGameServer/core/libs/guid"
+	"github.com/yicaoyimuys/GoGameServer/core/libs/kcp"
 	"github.com/yicaoyimuys/GoGameServer/core/libs/logger"
 	"github.com/yicaoyimuys/GoGameServer/core/libs/mongo"
 	"github.com/yicaoyimuys/GoGameServer/core/libs/mysql"
 	"github.com/yicaoyimuys/GoGameServer/core/libs/redis"
 	"github.com/yicaoyimuys/GoGameServer/core/libs/rpc"
+	"github.com/yicaoyimuys/GoGameServer/core/libs/sessions"
 	"github.com/yicaoyimuys/GoGameServer/core/libs/socket"
 	"github.com/yicaoyimuys/GoGameServer/core/libs/stack"
 	"github.com/yicaoyimuys/GoGameServer/core/libs/system"
@@ -44,6 +49,12 @@ type Service struct {
 
 	websocketServer *websocket.Server
 	socketServer    *socket.Server
+	kcpServer       *kcp.Server
+	admission       *admission.Admission
+	frontGuid       *guid.Guid
+
+	frontCreateHandle sessions.FrontSessionCreateHandle
+	frontCloseHandle  sessions.FrontSessionCloseHandle
 }
 
 func NewService(name string) *Service {
@@ -143,7 +154,13 @@ func (this *Service) registerService(serviceType string, servicePort string) {
 
 	//注册到Consul
 	serviceName := packageServiceName(serviceType, this.name)
-	err := consul.NewServive(this.ip, serviceName, this.id, servicePort)
+	consul.SetServiceWeight(config.GetService(this.name).ServiceNodes[this.id].Weight)
+	var err error
+	if serviceType == consts.ServiceType_Kcp {
+		err = consul.NewUdpServive(this.ip, serviceName, this.id, servicePort)
+	} else {
+		err = consul.NewServive(this.ip, serviceName, this.id, servicePort)
+	}
 	CheckError(err)
 
 	INFO("Join Consul Service", zap.String("ServiceName", serviceName), zap.String("ServicePort", servicePort))
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"github.com/yicaoyimuys/GoGameServer/core/config"
+	"github.com/yicaoyimuys/GoGameServer/core/consts"
+	"github.com/yicaoyimuys/GoGameServer/core/libs/admission"
+	"github.com/yicaoyimuys/GoGameServer/core/libs/guid"
+	"github.com/yicaoyimuys/GoGameServer/core/libs/sessions"
+)
+
+// StartFront 启动配置的前端服务，Socket、WebSocket、Kcp可使用不同端口同时启动，都未配置时默认为Socket
+func (this *Service) StartFront(handle sessions.FrontSessionReceiveMsgHandle) {
+	serviceConfig := config.GetService("connector")
+	serviceNodeConfig := serviceConfig.ServiceNodes[this.id]
+
+	//发送队列
+	sessions.FrontSessionSetSendQueue(serviceNodeConfig.SendQueueSize, serviceNodeConfig.SendQueuePolicy)
+
+	if getFrontPort(serviceNodeConfig, consts.ServiceType_WebSocket) != "" {
+		this.StartWebSocket(handle)
+	}
+	if getFrontPort(serviceNodeConfig, consts.ServiceType_Kcp) != "" {
+		this.StartKcp(handle)
+	}
+	if getFrontPort(serviceNodeConfig, consts.ServiceType_Socket) != "" || (this.websocketServer == nil && this.kcpServer == nil) {
+		this.StartSocket(handle)
+	}
+}
+
+// 前端服务端口，未单独配置时使用clientType对应的clientPort
+func getFrontPort(serviceNodeConfig config.ServiceNodeConfig, clientType string) string {
+	var port string
+	if clientType == consts.ServiceType_Socket {
+		port = serviceNodeConfig.SocketPort
+	} else if clientType == consts.ServiceType_WebSocket {
+		port = serviceNodeConfig.WebSocketPort
+	} else if clientType == consts.ServiceType_Kcp {
+		port = serviceNodeConfig.KcpPort
+	}
+	if port != "" {
+		return port
+	}
+
+	configType := serviceNodeConfig.ClientType
+	if configType == "" {
+		configType = consts.ServiceType_Socket
+	}
+	if configType == clientType {
+		return serviceNodeConfig.ClientPort
+	}
+	return ""
+}
+
+// 单独配置了端口的前端服务使用各自的TLS配置，使用clientPort时为useSSL
+func getFrontSSL(serviceNodeConfig config.ServiceNodeConfig, clientType string) bool {
+	if clientType == consts.ServiceType_Socket && serviceNodeConfig.SocketPort != "" {
+		return serviceNodeConfig.SocketSSL
+	}
+	if clientType == consts.ServiceType_WebSocket && serviceNodeConfig.WebSocketPort != "" {
+		return serviceNodeConfig.WebSocketSSL
+	}
+	return serviceNodeConfig.UseSSL
+}
+
+// 所有前端服务共用一个Guid，避免SessionID重复
+func (this *Service) getFrontGuid() *guid.Guid {
+	if this.frontGuid == nil {
+		this.frontGuid = guid.NewGuid(uint16(this.id))
+	}
+	return this.frontGuid
+}
+
+// 连接准入控制，所有前端服务共用
+func (this *Service) getAdmission() *admission.Admission {
+	if this.admission != nil {
+		return this.admission
+	}
+
+	serviceConfig := config.GetService("connector")
+	serviceNodeConfig := serviceConfig.ServiceNodes[this.id]
+	this.admission = admission.NewAdmission(serviceNodeConfig.MaxSessions, serviceNodeConfig.MaxConnPerIp,
+		serviceNodeConfig.ConnRatePerIp, serviceNodeConfig.ConnBurstPerIp)
+	this.admission.Start()
+	return this.admission
+}
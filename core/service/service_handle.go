@@ -2,51 +2,66 @@ package service
 
 import (
 	"github.com/yicaoyimuys/GoGameServer/core"
+	. "github.com/yicaoyimuys/GoGameServer/core/libs"
+	"github.com/yicaoyimuys/GoGameServer/core/libs/grpc/ipc"
 	"github.com/yicaoyimuys/GoGameServer/core/libs/sessions"
 	"github.com/yicaoyimuys/GoGameServer/core/libs/stack"
+	"go.uber.org/zap"
 )
 
-type ClientOffline struct {
-}
-
-type ClientOfflineReq struct {
-	ServiceIdentify string
-	UserSessionId   uint64
-}
-
-type ClientOfflineRes struct {
-}
-
-func (this *ClientOffline) Do(args *ClientOfflineReq, reply *ClientOfflineRes) error {
-	id := sessions.CreateBackSessionId(args.ServiceIdentify, args.UserSessionId)
-	session := sessions.GetBackSession(id)
-	if session != nil {
-		session.Close()
-	}
-	return nil
+// SetFrontSessionHandle 设置FrontSession创建和断开时的处理，需在StartFront之前调用
+func (this *Service) SetFrontSessionHandle(createHandle sessions.FrontSessionCreateHandle, closeHandle sessions.FrontSessionCloseHandle) {
+	this.frontCreateHandle = createHandle
+	this.frontCloseHandle = closeHandle
 }
 
 func (this *Service) frontSessionCreateHandle(session *sessions.FrontSession) {
 	session.AddCloseCallback(nil, "FrontSessionOffline", func() {
-		this.frontSessionOfflineHandle(session)
+		this.frontSessionCloseHandle(session)
 	})
+	if this.frontCreateHandle != nil {
+		this.frontCreateHandle(session)
+	}
 }
 
-func (this *Service) frontSessionOfflineHandle(session *sessions.FrontSession) {
-	method := "ClientOffline.Do"
-	args := &ClientOfflineReq{
-		ServiceIdentify: core.Service.Identify(),
-		UserSessionId:   session.ID(),
+func (this *Service) frontSessionCloseHandle(session *sessions.FrontSession) {
+	serviceIdentify := session.ServiceIdentify()
+	if serviceIdentify == "" {
+		serviceIdentify = core.Service.Identify()
+	}
+	userSessionId := session.ID()
+	offline := func() {
+		this.frontSessionOfflineHandle(session, serviceIdentify, userSessionId)
 	}
-	reply := &ClientOfflineRes{}
-
-	//通知后端服务器
-	for _, v := range this.rpcClients {
-		var client = v
-		go func() {
-			defer stack.TryError()
 
-			client.CallAll(method, args, reply)
-		}()
+	if this.frontCloseHandle != nil {
+		this.frontCloseHandle(session, offline)
+	} else {
+		offline()
 	}
 }
+
+// 通过Session使用过的ipc stream通知后端服务器，在该Session的最后一条消息之后发送
+func (this *Service) frontSessionOfflineHandle(session *sessions.FrontSession, serviceIdentify string, userSessionId uint64) {
+	go func() {
+		defer stack.TryError()
+
+		<-session.Handled()
+
+		req := &ipc.Req{
+			ServiceIdentify: serviceIdentify,
+			UserSessionId:   userSessionId,
+			Closed:          true,
+		}
+		for serviceName, service := range session.GetIpcServices() {
+			client := this.GetIpcClient(serviceName)
+			if client == nil {
+				continue
+			}
+			err := client.SendReq(req, service)
+			if err != nil {
+				ERR("通知客户端下线失败", zap.String("Service", service), zap.Uint64("UserSessionId", userSessionId), zap.Error(err))
+			}
+		}
+	}()
+}
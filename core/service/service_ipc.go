@@ -38,6 +38,9 @@ func (this *Service) StartIpcServer() {
 	//服务注册
 	this.registerService(consts.ServiceType_Ipc, port)
 
+	//负载上报
+	this.startLoadReport(consts.ServiceType_Ipc, port)
+
 	//Log
 	timer.DoTimer(20*1000, func() {
 		INFO("当前BackSession数量", zap.Int("BackSessionLen", sessions.BackSessionLen()))
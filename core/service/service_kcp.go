@@ -0,0 +1,39 @@
+package service
+
+import (
+	"github.com/yicaoyimuys/GoGameServer/core/config"
+	"github.com/yicaoyimuys/GoGameServer/core/consts"
+	"github.com/yicaoyimuys/GoGameServer/core/libs/kcp"
+	"github.com/yicaoyimuys/GoGameServer/core/libs/sessions"
+)
+
+func (this *Service) StartKcp(handle sessions.FrontSessionReceiveMsgHandle) {
+	//Kcp配置
+	serviceConfig := config.GetService("connector")
+	serviceNodeConfig := serviceConfig.ServiceNodes[this.id]
+	port := getFrontPort(serviceNodeConfig, consts.ServiceType_Kcp)
+
+	//创建Kcp Server
+	server := kcp.NewServer(port, this.id)
+	server.SetMaxFrameSize(serviceNodeConfig.MaxFrameSize)
+	server.SetCompressThreshold(serviceNodeConfig.CompressThreshold)
+	if serviceNodeConfig.UseCrypto {
+		server.SetCrypto(serviceConfig.CryptoKey)
+	}
+	server.SetGuid(this.getFrontGuid())
+	server.SetAdmission(this.getAdmission())
+	server.SetSessionCreateHandle(this.frontSessionCreateHandle)
+	server.SetSessionReceiveMsgHandle(handle)
+	err := server.Start()
+	if err != nil {
+		//前端端口不可用时不能继续运行
+		panic("Kcp Server Start Error: " + err.Error())
+	}
+	server.StartPing(serviceNodeConfig.PingTimeout, serviceNodeConfig.PingInterval)
+
+	//服务注册
+	this.registerService(consts.ServiceType_Kcp, port)
+
+	//service中保存kcpServer
+	this.kcpServer = server
+}
@@ -0,0 +1,61 @@
+package service
+
+import (
+	"runtime"
+	"time"
+
+	. "github.com/yicaoyimuys/GoGameServer/core/libs"
+	"github.com/yicaoyimuys/GoGameServer/core/libs/consul"
+	"github.com/yicaoyimuys/GoGameServer/core/libs/sessions"
+	"github.com/yicaoyimuys/GoGameServer/core/libs/stack"
+	"github.com/yicaoyimuys/GoGameServer/core/libs/system"
+	"github.com/yicaoyimuys/GoGameServer/core/libs/timer"
+	"go.uber.org/zap"
+)
+
+const (
+	//负载上报间隔(毫秒)
+	loadReportInterval = 3 * 1000
+)
+
+// 定时上报本进程负载到consul KV，用于connector按负载分配服务器
+func (this *Service) startLoadReport(serviceType string, servicePort string) {
+	err := consul.InitKV(true)
+	if err != nil {
+		ERR("负载上报开启失败", zap.Error(err))
+		return
+	}
+
+	serviceName := packageServiceName(serviceType, this.name)
+	serviceAddr := this.ip + ":" + servicePort
+	lastCpuTime := system.ProcessCpuTime()
+	lastTime := time.Now()
+
+	timer.DoTimer(loadReportInterval, func() {
+		defer stack.TryError()
+
+		//CPU使用率
+		now := time.Now()
+		cpuTime := system.ProcessCpuTime()
+		cpu := 0.0
+		if elapsed := now.Sub(lastTime); elapsed > 0 {
+			cpu = float64(cpuTime-lastCpuTime) / float64(elapsed)
+		}
+		if maxCpu := float64(runtime.NumCPU()); cpu > maxCpu {
+			cpu = maxCpu
+		}
+		lastCpuTime = cpuTime
+		lastTime = now
+
+		load := &consul.ServiceLoad{
+			Sessions: sessions.BackSessionLen(),
+			Cpu:      cpu,
+			Queue:    sessions.BackSessionQueueLen(),
+			Time:     now.Unix(),
+		}
+		err := consul.SetServiceLoad(serviceName, serviceAddr, load)
+		if err != nil {
+			ERR("负载上报失败", zap.String("ServiceName", serviceName), zap.Error(err))
+		}
+	})
+}
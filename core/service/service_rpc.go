@@ -29,10 +29,6 @@ func (this *Service) StartRpcServer() {
 	CheckError(err)
 	INFO("Rpc Server Start", zap.String("Port", port))
 
-	//注册客户端下线回调
-	err = rpc.RegisterModule("ClientOffline", &ClientOffline{})
-	CheckError(err)
-
 	//服务注册
 	this.registerService(consts.ServiceType_Rpc, port)
 }
@@ -11,14 +11,32 @@ func (this *Service) StartSocket(handle sessions.FrontSessionReceiveMsgHandle) {
 	//Socket配置
 	serviceConfig := config.GetService("connector")
 	serviceNodeConfig := serviceConfig.ServiceNodes[this.id]
-	port := serviceNodeConfig.ClientPort
+	port := getFrontPort(serviceNodeConfig, consts.ServiceType_Socket)
+	useSSL := getFrontSSL(serviceNodeConfig, consts.ServiceType_Socket)
 
 	//创建Socket Server
 	server := socket.NewServer(port, this.id)
+	if useSSL {
+		tslCrt := serviceConfig.TslCrt
+		tslKey := serviceConfig.TslKey
+		server.SetTLS(tslCrt, tslKey)
+	}
+	if serviceNodeConfig.UseCrypto {
+		server.SetCrypto(serviceConfig.CryptoKey)
+	}
+	server.SetProxyProtocol(serviceNodeConfig.ProxyProtocol)
+	server.SetMaxFrameSize(serviceNodeConfig.MaxFrameSize)
+	server.SetCompressThreshold(serviceNodeConfig.CompressThreshold)
+	server.SetGuid(this.getFrontGuid())
+	server.SetAdmission(this.getAdmission())
 	server.SetSessionCreateHandle(this.frontSessionCreateHandle)
 	server.SetSessionReceiveMsgHandle(handle)
-	server.Start()
-	server.StartPing()
+	err := server.Start()
+	if err != nil {
+		//前端端口不可用时不能继续运行
+		panic("Socket Server Start Error: " + err.Error())
+	}
+	server.StartPing(serviceNodeConfig.PingTimeout, serviceNodeConfig.PingInterval)
 
 	//服务注册
 	this.registerService(consts.ServiceType_Socket, port)
@@ -11,8 +11,8 @@ func (this *Service) StartWebSocket(handle sessions.FrontSessionReceiveMsgHandle
 	//WebSocket配置
 	serviceConfig := config.GetService("connector")
 	serviceNodeConfig := serviceConfig.ServiceNodes[this.id]
-	port := serviceNodeConfig.ClientPort
-	useSSL := serviceNodeConfig.UseSSL
+	port := getFrontPort(serviceNodeConfig, consts.ServiceType_WebSocket)
+	useSSL := getFrontSSL(serviceNodeConfig, consts.ServiceType_WebSocket)
 
 	//创建WebSocket Server
 	server := websocket.NewServer(port, this.id)
@@ -21,10 +21,18 @@ func (this *Service) StartWebSocket(handle sessions.FrontSessionReceiveMsgHandle
 		tslKey := serviceConfig.TslKey
 		server.SetTLS(tslCrt, tslKey)
 	}
+	server.SetMaxFrameSize(serviceNodeConfig.MaxFrameSize)
+	server.SetCompressThreshold(serviceNodeConfig.CompressThreshold)
+	server.SetGuid(this.getFrontGuid())
+	server.SetAdmission(this.getAdmission())
+	server.SetJsonDebug(serviceNodeConfig.JsonDebug)
+	server.SetPath(serviceNodeConfig.WebSocketPath)
+	server.SetAllowOrigins(serviceNodeConfig.AllowOrigins)
+	server.SetTrustedProxies(serviceNodeConfig.TrustedProxies)
 	server.SetSessionCreateHandle(this.frontSessionCreateHandle)
 	server.SetSessionReceiveMsgHandle(handle)
 	server.Start()
-	server.StartPing()
+	server.StartPing(serviceNodeConfig.PingTimeout, serviceNodeConfig.PingInterval)
 
 	//服务注册
 	this.registerService(consts.ServiceType_WebSocket, port)
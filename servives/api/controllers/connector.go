@@ -52,6 +52,8 @@ func (this *ConnectorController) Get() {
 		serviceName = packageServiceName(consts.ServiceType_Socket, consts.Service_Connector)
 	} else if typeStr == "WebSocket" {
 		serviceName = packageServiceName(consts.ServiceType_WebSocket, consts.Service_Connector)
+	} else if typeStr == "Kcp" {
+		serviceName = packageServiceName(consts.ServiceType_Kcp, consts.Service_Connector)
 	} else {
 		ERR("Invalid service type", zap.String("type", typeStr))
 		this.Data["json"] = []string{}
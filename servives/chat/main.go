@@ -6,6 +6,7 @@ import (
 	"github.com/yicaoyimuys/GoGameServer/core/messages"
 	"github.com/yicaoyimuys/GoGameServer/core/service"
 	"github.com/yicaoyimuys/GoGameServer/servives/chat/module"
+	"github.com/yicaoyimuys/GoGameServer/servives/public"
 	"github.com/yicaoyimuys/GoGameServer/servives/public/gameProto"
 )
 
@@ -28,8 +29,13 @@ func main() {
 }
 
 func initMessage() {
-	messages.RegisterIpcServerHandle(gameProto.ID_user_joinChat_c2s, module.JoinChat)
-	messages.RegisterIpcServerHandle(gameProto.ID_user_chat_c2s, module.Chat)
+	//所有消息的中间件
+	public.UseDefaultMiddleware()
+	//消息处理返回错误时回复客户端错误码
+	messages.SetIpcServerErrorHandle(public.ReplyErrorToClient)
+
+	messages.RegisterIpcServerRequest(gameProto.ID_user_joinChat_c2s, module.JoinChat, messages.RequireLogin())
+	messages.RegisterIpcServerHandle(gameProto.ID_user_chat_c2s, module.Chat, messages.RequireLogin(), public.RateLimit(2, 5), public.Validate(module.CheckChat))
 }
 
 func initModule() {
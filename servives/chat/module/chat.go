@@ -1,9 +1,12 @@
 package module
 
 import (
+	"unicode/utf8"
+
 	. "github.com/yicaoyimuys/GoGameServer/core/libs"
 	"github.com/yicaoyimuys/GoGameServer/core/libs/protos"
 	"github.com/yicaoyimuys/GoGameServer/core/libs/sessions"
+	"github.com/yicaoyimuys/GoGameServer/core/messages"
 	"github.com/yicaoyimuys/GoGameServer/servives/chat/cache"
 	"github.com/yicaoyimuys/GoGameServer/servives/public"
 	"github.com/yicaoyimuys/GoGameServer/servives/public/errCodes"
@@ -14,26 +17,26 @@ import (
 	"google.golang.org/protobuf/proto"
 )
 
+const (
+	//世界频道，加入聊天的用户都在该分组中
+	worldGroup = "chat.world"
+	//聊天内容最大长度
+	chatMsgMaxLen = 200
+)
+
 // 获取用户信息
-func JoinChat(clientSession *sessions.BackSession, msgData proto.Message) {
-	data := msgData.(*gameProto.UserJoinChatC2S)
-	token := data.GetToken()
-	userId := public.GetUserIdByToken(token)
-	if userId == 0 {
-		public.SendErrorMsgToClient(clientSession, errCodes.PARAM_ERROR)
-		return
-	}
+func JoinChat(ctx *messages.Context, req *gameProto.UserJoinChatC2S) (*gameProto.UserJoinChatS2C, error) {
+	clientSession := ctx.Session
 
-	//获取redis缓存中用户数据
-	dbUser := redisCaches.GetUser(userId)
+	//获取redis缓存中用户数据，已由RequireLogin检查登录
+	dbUser := redisCaches.GetUser(ctx.UserID())
 	if dbUser == nil {
-		public.SendErrorMsgToClient(clientSession, errCodes.PARAM_ERROR)
-		return
+		return nil, errCodes.ErrParam
 	}
 
 	//保存到内存中
-	clientSession.SetUserId(dbUser.Id)
 	cache.AddUser(dbUser.Id, dbUser.Account, clientSession)
+	public.JoinGroup(clientSession, worldGroup)
 
 	//用户下线处理
 	clientSession.AddCloseCallback(nil, "user.joinChatSuccess", func() {
@@ -44,7 +47,7 @@ func JoinChat(clientSession *sessions.BackSession, msgData proto.Message) {
 
 	//返回客户端
 	sendMsg := &gameProto.UserJoinChatS2C{}
-	public.SendMsgToClient(clientSession, sendMsg)
+	return sendMsg, nil
 }
 
 func Chat(clientSession *sessions.BackSession, msgData proto.Message) {
@@ -55,11 +58,18 @@ func Chat(clientSession *sessions.BackSession, msgData proto.Message) {
 		return
 	}
 
-	//发送给所有人
+	//发送给世界频道中的所有人
 	sendMsg := &gameProto.UserChatNoticeS2C{
 		UserId:   protos.Uint64(chatUser.UserID),
 		UserName: protos.String(chatUser.UserName),
 		Msg:      protos.String(data.GetMsg()),
 	}
-	public.SendMsgToAllClient(sendMsg)
+	public.SendMsgToGroup(worldGroup, sendMsg)
+}
+
+// CheckChat 聊天内容不能为空或过长
+func CheckChat(msgData proto.Message) bool {
+	data := msgData.(*gameProto.UserChatC2S)
+	msgLen := utf8.RuneCountInString(data.GetMsg())
+	return msgLen > 0 && msgLen <= chatMsgMaxLen
 }
@@ -0,0 +1,125 @@
+package cache
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/yicaoyimuys/GoGameServer/servives/public/redisInstances"
+	"github.com/yicaoyimuys/GoGameServer/servives/public/redisKeys"
+
+	"github.com/spf13/cast"
+)
+
+// 断线等待重连的Session
+type ResumeSession struct {
+	Connector       string            `json:"connector"` //断线时所在的connector
+	ServiceIdentify string            `json:"serviceIdentify"`
+	SessionId       uint64            `json:"sessionId"`
+	UserId          uint64            `json:"userId"`
+	IpcServices     map[string]string `json:"ipcServices"`
+	Groups          []string          `json:"groups"`
+	Attrs           map[string]string `json:"attrs"`
+	ReliableSeq     uint32            `json:"reliableSeq"`
+	ReliableMsgs    []ReliableMsg     `json:"reliableMsgs"`
+}
+
+// 已发送但客户端未确认的消息
+type ReliableMsg struct {
+	Seq  uint32 `json:"seq"`
+	Data []byte `json:"data"`
+}
+
+// 断线期间发送给客户端的消息
+type ResumeMsg struct {
+	Data     []byte
+	Reliable bool
+}
+
+func SetResumeSession(token string, session *ResumeSession, expiration time.Duration) error {
+	key := redisKeys.ResumeSession + token
+	data, _ := json.Marshal(session)
+	return redisInstances.Global().Set(key, data, expiration).Err()
+}
+
+// 取出并删除，同一个token只能被取出一次
+func TakeResumeSession(token string) *ResumeSession {
+	key := redisKeys.ResumeSession + token
+	val, err := redisInstances.Global().GetDel(key).Result()
+	if err != nil {
+		return nil
+	}
+
+	var session ResumeSession
+	err = json.Unmarshal([]byte(val), &session)
+	if err != nil {
+		return nil
+	}
+	return &session
+}
+
+// 缓存断线期间发送给客户端的消息，超出maxLen时丢弃最早的消息
+func AddResumeMsg(sessionId uint64, msg *ResumeMsg, maxLen int64, expiration time.Duration) error {
+	key := redisKeys.ResumeSessionMsg + cast.ToString(sessionId)
+	redisClient := redisInstances.Global()
+
+	//第一个字节标记是否需要确认
+	data := make([]byte, 1+len(msg.Data))
+	if msg.Reliable {
+		data[0] = 1
+	}
+	copy(data[1:], msg.Data)
+
+	err := redisClient.RPush(key, data).Err()
+	if err != nil {
+		return err
+	}
+	redisClient.LTrim(key, -maxLen, -1)
+	return redisClient.Expire(key, expiration).Err()
+}
+
+func TakeResumeMsgs(sessionId uint64) []*ResumeMsg {
+	key := redisKeys.ResumeSessionMsg + cast.ToString(sessionId)
+	vals, err := redisInstances.Global().LTake(key).Result()
+	if err != nil {
+		return nil
+	}
+
+	msgs := make([]*ResumeMsg, 0, len(vals))
+	for _, val := range vals {
+		if len(val) == 0 {
+			continue
+		}
+		msgs = append(msgs, &ResumeMsg{
+			Data:     []byte(val[1:]),
+			Reliable: val[0] == 1,
+		})
+	}
+	return msgs
+}
+
+func DelResumeMsgs(sessionId uint64) {
+	key := redisKeys.ResumeSessionMsg + cast.ToString(sessionId)
+	redisInstances.Global().Del(key)
+}
+
+// AddResumeMoved 原connector收到一个后端服务器的切换通知，之前该服务器发送的消息都已缓存
+func AddResumeMoved(sessionId uint64, expiration time.Duration) error {
+	key := redisKeys.ResumeSessionMoved + cast.ToString(sessionId)
+	redisClient := redisInstances.Global()
+	err := redisClient.Incr(key).Err()
+	if err != nil {
+		return err
+	}
+	return redisClient.Expire(key, expiration).Err()
+}
+
+func GetResumeMoved(sessionId uint64) int {
+	key := redisKeys.ResumeSessionMoved + cast.ToString(sessionId)
+	val, _ := redisInstances.Global().Get(key).Int()
+	return val
+}
+
+func DelResumeMoved(sessionId uint64) {
+	key := redisKeys.ResumeSessionMoved + cast.ToString(sessionId)
+	redisInstances.Global().Del(key)
+}
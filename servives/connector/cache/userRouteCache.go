@@ -0,0 +1,60 @@
+package cache
+
+import (
+	"time"
+
+	"github.com/yicaoyimuys/GoGameServer/servives/public/redisInstances"
+	"github.com/yicaoyimuys/GoGameServer/servives/public/redisKeys"
+
+	"github.com/go-redis/redis"
+	"github.com/spf13/cast"
+)
+
+// 用户所在的后端服务器，key为服务名
+// 未记录时返回空，Redis不可用时返回错误
+func GetUserRoute(userId uint64, serviceName string) (string, error) {
+	key := redisKeys.RouteUser + cast.ToString(userId)
+	val, err := redisInstances.Global().HGet(key, serviceName).Result()
+	if err == redis.Nil {
+		return "", nil
+	}
+	return val, err
+}
+
+// 未记录时才保存，多个connector同时分配时以先保存的为准，返回最终记录的服务器
+func AddUserRoute(userId uint64, serviceName string, service string, expiration time.Duration) (string, error) {
+	key := redisKeys.RouteUser + cast.ToString(userId)
+	redisClient := redisInstances.Global()
+
+	ok, err := redisClient.HSetNX(key, serviceName, service).Result()
+	if err != nil {
+		return "", err
+	}
+	redisClient.Expire(key, expiration)
+	if ok {
+		return service, nil
+	}
+
+	val, err := redisClient.HGet(key, serviceName).Result()
+	if err != nil {
+		return "", err
+	}
+	return val, nil
+}
+
+// 原服务器已下线时重新分配
+func SetUserRoute(userId uint64, serviceName string, service string, expiration time.Duration) error {
+	key := redisKeys.RouteUser + cast.ToString(userId)
+	redisClient := redisInstances.Global()
+
+	err := redisClient.HSet(key, serviceName, service).Err()
+	if err != nil {
+		return err
+	}
+	return redisClient.Expire(key, expiration).Err()
+}
+
+func RefreshUserRoute(userId uint64, expiration time.Duration) error {
+	key := redisKeys.RouteUser + cast.ToString(userId)
+	return redisInstances.Global().Expire(key, expiration).Err()
+}
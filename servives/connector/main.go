@@ -6,16 +6,23 @@ import (
 	"github.com/yicaoyimuys/GoGameServer/core/service"
 	"github.com/yicaoyimuys/GoGameServer/servives/connector/messages"
 	"github.com/yicaoyimuys/GoGameServer/servives/connector/module"
+	"github.com/yicaoyimuys/GoGameServer/servives/connector/monitor"
 )
 
 func main() {
 	//初始化Service
 	newService := service.NewService(consts.Service_Connector)
 	newService.StartRedis()
-	// newService.StartWebSocket(messages.FontReceive)
-	newService.StartSocket(messages.FontReceive)
-	newService.StartIpcClient([]string{consts.Service_Game, consts.Service_Login, consts.Service_Chat})
-	newService.StartRpcClient([]string{consts.Service_Game, consts.Service_Login, consts.Service_Chat, consts.Service_Log})
+	newService.SetFrontSessionHandle(module.FrontSessionCreate, module.FrontSessionClose)
+	module.InitResume()
+	module.InitReliable()
+	module.InitRequest()
+	module.InitUserRoute()
+	module.InitControl()
+	messages.InitRoute()
+	newService.StartFront(messages.FontReceive)
+	newService.StartIpcClient(messages.RouteServices())
+	newService.StartRpcClient([]string{consts.Service_Log})
 	newService.StartPProf(6000)
 
 	//模块初始化
@@ -30,4 +37,7 @@ func initModule() {
 	//sessions.StartSessionCleanup()
 
 	module.StartServerTimer()
+	module.StartRateLimit()
+	module.StartHeartbeat()
+	monitor.Start()
 }
@@ -4,6 +4,8 @@ import (
 	. "github.com/yicaoyimuys/GoGameServer/core/libs"
 	"github.com/yicaoyimuys/GoGameServer/core/libs/protos"
 	"github.com/yicaoyimuys/GoGameServer/core/libs/sessions"
+	"github.com/yicaoyimuys/GoGameServer/servives/connector/module"
+	"github.com/yicaoyimuys/GoGameServer/servives/public/gameProto"
 	"go.uber.org/zap"
 )
 
@@ -12,6 +14,22 @@ func FontReceive(session *sessions.FrontSession, msgBody []byte) {
 	msgId := protos.UnmarshalProtoId(msgBody)
 	//DEBUG("FrontMessage收到消息ID：", msgId)
 
+	//带序号的请求，取出原消息
+	var requestSeq uint32
+	if msgId == gameProto.ID_client_request_c2s {
+		requestSeq, msgBody = module.UnpackRequest(msgBody)
+		if msgBody == nil {
+			ERR("请求消息格式错误", zap.Uint64("SessionId", session.ID()))
+			return
+		}
+		msgId = protos.UnmarshalProtoId(msgBody)
+	}
+
+	//限流
+	if !module.CheckRateLimit(session, msgId, getMsgRange(msgId), requestSeq) {
+		return
+	}
+
 	//消息处理
 	if isSystemMsg(msgId) {
 		//系统消息
@@ -19,36 +37,35 @@ func FontReceive(session *sessions.FrontSession, msgBody []byte) {
 	} else if isConnectorMsg(msgId) {
 		//连接服务器消息
 		dealConnectorMsg(session, msgBody)
-	} else if isLoginMsg(msgId) {
-		//登录服务器消息
-		dealLoginMsg(session, msgBody)
-	} else if isGameMsg(msgId) {
-		//游戏服务器消息
-		dealGameMsg(session, msgBody)
-	} else if isChatMsg(msgId) {
-		//聊天服务器消息
-		dealChatMsg(session, msgBody)
+	} else if route := getRoute(msgId); route != nil {
+		//后端服务器消息
+		dealRouteMsg(session, route, msgBody, requestSeq)
 	} else {
 		ERR("WHAT???", zap.Uint16("MsgId", msgId))
 	}
 }
 
-func isSystemMsg(msgId uint16) bool {
-	return msgId >= 1 && msgId <= 999
-}
-
-func isConnectorMsg(msgId uint16) bool {
-	return msgId >= 1000 && msgId <= 1999
+// 消息范围，用于限流规则：system、connector或路由表中的服务名
+func getMsgRange(msgId uint16) string {
+	if isSystemMsg(msgId) {
+		return "system"
+	} else if isConnectorMsg(msgId) {
+		return "connector"
+	} else if route := getRoute(msgId); route != nil {
+		return route.service
+	}
+	return ""
 }
 
-func isLoginMsg(msgId uint16) bool {
-	return msgId >= 2000 && msgId <= 2999
-}
+const (
+	systemMaxMsgId    = 999
+	connectorMaxMsgId = 1999
+)
 
-func isGameMsg(msgId uint16) bool {
-	return msgId >= 3000 && msgId <= 3999
+func isSystemMsg(msgId uint16) bool {
+	return msgId >= 1 && msgId <= systemMaxMsgId
 }
 
-func isChatMsg(msgId uint16) bool {
-	return msgId >= 4000 && msgId <= 4999
+func isConnectorMsg(msgId uint16) bool {
+	return msgId > systemMaxMsgId && msgId <= connectorMaxMsgId
 }
@@ -10,8 +10,12 @@ import (
 	"github.com/yicaoyimuys/GoGameServer/core/libs/protos"
 	"github.com/yicaoyimuys/GoGameServer/core/libs/sessions"
 	"github.com/yicaoyimuys/GoGameServer/core/libs/stack"
+	"github.com/yicaoyimuys/GoGameServer/servives/connector/module"
+	"github.com/yicaoyimuys/GoGameServer/servives/public/errCodes"
 	"github.com/yicaoyimuys/GoGameServer/servives/public/gameProto"
 	"go.uber.org/zap"
+
+	"github.com/spf13/cast"
 )
 
 func dealConnectorMsg(session *sessions.FrontSession, msgBody []byte) {
@@ -25,91 +29,113 @@ func dealConnectorMsg(session *sessions.FrontSession, msgBody []byte) {
 		session.UpdatePingTime()
 		return
 	}
-}
 
-func dealGameMsg(session *sessions.FrontSession, msgBody []byte) {
-	err := sendMsgToIpcService(consts.Service_Game, session, msgBody)
-	if err != nil {
-		ERR("DealGameMsg", zap.Error(err))
-		sendErrorMsgToClient(session)
+	//服务器ping回复
+	if protoMsg.ID == gameProto.ID_client_pong_c2s {
+		module.Pong(session)
+		return
 	}
-}
 
-func dealChatMsg(session *sessions.FrontSession, msgBody []byte) {
-	err := sendMsgToIpcService(consts.Service_Chat, session, msgBody)
-	if err != nil {
-		ERR("DealChatMsg", zap.Error(err))
-		sendErrorMsgToClient(session)
+	//消息确认
+	if protoMsg.ID == gameProto.ID_client_ack_c2s {
+		protoMsgData := protoMsg.Body.(*gameProto.ClientAckC2S)
+		module.AckReliable(session, protoMsgData.GetSeq())
+		return
+	}
+
+	//断线重连
+	if protoMsg.ID == gameProto.ID_client_resume_c2s {
+		protoMsgData := protoMsg.Body.(*gameProto.ClientResumeC2S)
+		module.Resume(session, protoMsgData.GetToken())
+		return
 	}
 }
 
-func dealLoginMsg(session *sessions.FrontSession, msgBody []byte) {
-	err := sendMsgToIpcService(consts.Service_Login, session, msgBody)
+func dealRouteMsg(session *sessions.FrontSession, route *route, msgBody []byte, requestSeq uint32) {
+	//需登录后才能发送
+	if route.requireLogin && session.UserId() == 0 {
+		msgId := protos.UnmarshalProtoId(msgBody)
+		module.SendErrorResponse(session, requestSeq, msgId, errCodes.NOT_LOGIN)
+		return
+	}
+
+	err := sendMsgToIpcService(route, session, msgBody, requestSeq)
 	if err != nil {
-		ERR("DealLoginMsg", zap.Error(err))
-		sendErrorMsgToClient(session)
+		ERR("DealRouteMsg", zap.String("Service", route.service), zap.Error(err))
+		sendErrorMsgToClient(session, msgBody, requestSeq)
 	}
 }
 
-func getGameService(session *sessions.FrontSession, msgBody []byte, ipcClient *ipc.Client) string {
-	//1: 获取用户数据，根据Token分配
+// 后端服务器代替客户端发送的消息，不检查登录，回复直接发送给客户端
+func dealForwardMsg(session *sessions.FrontSession, serviceName string, service string, msgBody []byte) {
+	//客户端已断开，避免后端服务器重新创建Session
+	if session.IsClosed() {
+		return
+	}
+
 	msgId := protos.UnmarshalProtoId(msgBody)
-	if msgId == gameProto.ID_user_getInfo_c2s {
-		protoMsg := protos.UnmarshalProtoMsg(msgBody)
-		if protoMsg == protos.NullProtoMsg {
-			return ""
-		}
-		protoMsgData := protoMsg.Body.(*gameProto.UserGetInfoC2S)
-		return ipcClient.GetServiceByFlag(protoMsgData.GetToken())
-	} else {
-		return session.GetIpcService(consts.Service_Game)
+	route := getRouteByService(serviceName)
+	if route == nil || getRoute(msgId) != route {
+		ERR("转发消息的服务不存在", zap.String("Service", serviceName), zap.Uint16("MsgId", msgId))
+		return
+	}
+
+	if service != "" {
+		session.SetIpcService(serviceName, service)
+	}
+	err := sendMsgToIpcService(route, session, msgBody, 0)
+	if err != nil {
+		ERR("DealForwardMsg", zap.String("Service", serviceName), zap.Error(err))
 	}
 }
 
-func getChatService(session *sessions.FrontSession, msgBody []byte, ipcClient *ipc.Client) string {
-	//1: 加入聊天，根据Token分配
-	msgId := protos.UnmarshalProtoId(msgBody)
-	if msgId == gameProto.ID_user_joinChat_c2s {
-		protoMsg := protos.UnmarshalProtoMsg(msgBody)
-		if protoMsg == protos.NullProtoMsg {
-			return ""
-		}
-		protoMsgData := protoMsg.Body.(*gameProto.UserJoinChatC2S)
-		return ipcClient.GetServiceByFlag(protoMsgData.GetToken())
-	} else {
-		return session.GetIpcService(consts.Service_Chat)
+// 按路由表中的字段分配服务器，其他消息发送到已分配的服务器，未分配时返回空
+// 开启userRoute的服务按登录绑定的用户ID分配，不使用路由字段
+func getRouteService(session *sessions.FrontSession, route *route, msgBody []byte, ipcClient *ipc.Client) string {
+	if route.userRoute {
+		return getUserRouteService(session, route, ipcClient)
 	}
+
+	key, sticky := route.getStickyKey(msgBody)
+	if !sticky {
+		return session.GetIpcService(route.service)
+	}
+	if key == "" {
+		return ""
+	}
+	return ipcClient.GetServiceByFlag(key)
 }
 
-func getLoginService(session *sessions.FrontSession, msgBody []byte, ipcClient *ipc.Client) string {
-	//1: 登录，根据Account分配
-	msgId := protos.UnmarshalProtoId(msgBody)
-	if msgId == gameProto.ID_user_login_c2s {
-		protoMsg := protos.UnmarshalProtoMsg(msgBody)
-		if protoMsg == protos.NullProtoMsg {
-			return ""
-		}
-		protoMsgData := protoMsg.Body.(*gameProto.UserLoginC2S)
-		return ipcClient.GetServiceByFlag(protoMsgData.GetAccount())
-	} else {
-		return session.GetIpcService(consts.Service_Login)
+// 已分配的服务器可用时直接使用，否则优先使用Redis中记录的用户所在服务器，首次分配按负载，未登录时返回空
+func getUserRouteService(session *sessions.FrontSession, route *route, ipcClient *ipc.Client) string {
+	userId := session.UserId()
+	if userId == 0 {
+		return ""
+	}
+
+	services := ipcClient.GetServiceWeights()
+	service := session.GetIpcService(route.service)
+	if _, ok := services[service]; ok {
+		return service
 	}
+
+	service, err := module.UserRouteService(userId, route.service, ipcClient.GetServiceByLoad, services)
+	if err != nil {
+		//Redis不可用时按用户ID一致性哈希分配，保证同一用户分配到相同的服务器
+		ERR("获取用户路由失败", zap.Uint64("UserId", userId), zap.Error(err))
+		return ipcClient.GetServiceByFlag(cast.ToString(userId))
+	}
+	return service
 }
 
-func sendErrorMsgToClient(session *sessions.FrontSession) {
-	sendMsg := protos.MarshalProtoMsg(&gameProto.ErrorNoticeS2C{
-		ErrorCode: protos.Int32(consts.ErrCode_SystemError),
-	})
-	session.Send(sendMsg)
+func sendErrorMsgToClient(session *sessions.FrontSession, msgBody []byte, requestSeq uint32) {
+	msgId := protos.UnmarshalProtoId(msgBody)
+	module.SendErrorResponse(session, requestSeq, msgId, consts.ErrCode_SystemError)
 }
 
-func sendMsgToIpcService(serviceName string, clientSession *sessions.FrontSession, msgBody []byte) error {
+func sendMsgToIpcService(route *route, clientSession *sessions.FrontSession, msgBody []byte, requestSeq uint32) error {
 	defer stack.TryError()
 
-	if serviceName == "" {
-		return errors.New("serviceName is empty")
-	}
-
 	if clientSession == nil {
 		return errors.New("clientSession is nil")
 	}
@@ -122,27 +148,25 @@ func sendMsgToIpcService(serviceName string, clientSession *sessions.FrontSessio
 		return errors.New("Service is nil")
 	}
 
+	serviceName := route.service
 	ipcClient := core.Service.GetIpcClient(serviceName)
 	if ipcClient == nil {
 		return errors.New(serviceName + ": ipcClient not exists")
 	}
 
-	var service string
-	if serviceName == consts.Service_Login {
-		service = getLoginService(clientSession, msgBody, ipcClient)
-	} else if serviceName == consts.Service_Game {
-		service = getGameService(clientSession, msgBody, ipcClient)
-	} else if serviceName == consts.Service_Chat {
-		service = getChatService(clientSession, msgBody, ipcClient)
-	} else {
-		return errors.New("unknown service: " + serviceName)
-	}
-
+	service := getRouteService(clientSession, route, msgBody, ipcClient)
 	if service == "" {
 		return errors.New(serviceName + ": service not exists")
 	}
 
-	err := ipcClient.Send(core.Service.Identify(), clientSession.ID(), msgBody, service)
+	err := ipcClient.SendReq(&ipc.Req{
+		ServiceIdentify: module.ServiceIdentify(clientSession),
+		UserSessionId:   clientSession.ID(),
+		RemoteAddr:      clientSession.RemoteAddr(),
+		UserId:          clientSession.UserId(),
+		Data:            msgBody,
+		RequestSeq:      requestSeq,
+	}, service)
 	if err == nil {
 		clientSession.SetIpcService(serviceName, service)
 	}
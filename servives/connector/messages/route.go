@@ -0,0 +1,118 @@
+package messages
+
+import (
+	"github.com/yicaoyimuys/GoGameServer/core/config"
+	. "github.com/yicaoyimuys/GoGameServer/core/libs"
+	"github.com/yicaoyimuys/GoGameServer/core/libs/protos"
+	coreMessages "github.com/yicaoyimuys/GoGameServer/core/messages"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+type route struct {
+	service      string
+	minMsgId     uint16
+	maxMsgId     uint16
+	stickyKeys   map[uint16]protoreflect.Name
+	requireLogin bool
+	userRoute    bool
+}
+
+var (
+	routes []*route
+)
+
+// InitRoute 加载route.json中的路由表，新增后端服务只需修改配置
+func InitRoute() {
+	routes = []*route{}
+	for _, v := range config.GetRouteConfig() {
+		if v.Service == "" || v.MinMsgId > v.MaxMsgId {
+			ERR("路由配置错误", zap.Any("Route", v))
+			continue
+		}
+		if v.MinMsgId <= connectorMaxMsgId {
+			ERR("路由消息范围与系统消息冲突", zap.Any("Route", v))
+			continue
+		}
+		if isRouteOverlap(v.MinMsgId, v.MaxMsgId) {
+			ERR("路由消息范围重复", zap.Any("Route", v))
+			continue
+		}
+
+		newRoute := &route{
+			service:      v.Service,
+			minMsgId:     v.MinMsgId,
+			maxMsgId:     v.MaxMsgId,
+			stickyKeys:   make(map[uint16]protoreflect.Name),
+			requireLogin: v.RequireLogin,
+			userRoute:    v.UserRoute,
+		}
+		for msgId, key := range v.StickyKeys {
+			newRoute.stickyKeys[msgId] = protoreflect.Name(key)
+		}
+		routes = append(routes, newRoute)
+	}
+	INFO("路由表已加载", zap.Int("RouteNum", len(routes)))
+
+	//后端服务器之间通过connector转发消息
+	coreMessages.SetFrontSessionForwardHandle(dealForwardMsg)
+}
+
+// RouteServices 路由表中的所有后端服务
+func RouteServices() []string {
+	services := []string{}
+	for _, v := range config.GetRouteConfig() {
+		if v.Service != "" {
+			services = append(services, v.Service)
+		}
+	}
+	return services
+}
+
+func getRoute(msgId uint16) *route {
+	for _, v := range routes {
+		if msgId >= v.minMsgId && msgId <= v.maxMsgId {
+			return v
+		}
+	}
+	return nil
+}
+
+func getRouteByService(serviceName string) *route {
+	for _, v := range routes {
+		if v.service == serviceName {
+			return v
+		}
+	}
+	return nil
+}
+
+func isRouteOverlap(minMsgId uint16, maxMsgId uint16) bool {
+	for _, v := range routes {
+		if minMsgId <= v.maxMsgId && v.minMsgId <= maxMsgId {
+			return true
+		}
+	}
+	return false
+}
+
+// 取出消息中分配服务器使用的字段值，不需要按字段分配时返回false
+func (this *route) getStickyKey(msgBody []byte) (string, bool) {
+	msgId := protos.UnmarshalProtoId(msgBody)
+	key, ok := this.stickyKeys[msgId]
+	if !ok {
+		return "", false
+	}
+
+	protoMsg := protos.UnmarshalProtoMsg(msgBody)
+	if protoMsg == protos.NullProtoMsg {
+		return "", true
+	}
+	message := protoMsg.Body.ProtoReflect()
+	field := message.Descriptor().Fields().ByName(key)
+	if field == nil {
+		ERR("路由字段不存在", zap.Uint16("MsgId", msgId), zap.String("Key", string(key)))
+		return "", true
+	}
+	return message.Get(field).String(), true
+}
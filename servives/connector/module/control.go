@@ -0,0 +1,43 @@
+package module
+
+import (
+	. "github.com/yicaoyimuys/GoGameServer/core/libs"
+	"github.com/yicaoyimuys/GoGameServer/core/libs/protos"
+	"github.com/yicaoyimuys/GoGameServer/core/libs/sessions"
+	"github.com/yicaoyimuys/GoGameServer/core/messages"
+	"github.com/yicaoyimuys/GoGameServer/servives/connector/cache"
+	"github.com/yicaoyimuys/GoGameServer/servives/public/gameProto"
+	"go.uber.org/zap"
+)
+
+// InitControl 处理后端服务器发送的踢下线、指定服务器等控制消息
+func InitControl() {
+	messages.SetFrontSessionKickHandle(kick)
+	messages.SetFrontSessionPinHandle(pin)
+}
+
+// 通知客户端原因后断开连接，不保留断线重连
+func kick(session *sessions.FrontSession, reason string, data []byte) {
+	INFO("踢下线", zap.Uint64("SessionId", session.ID()), zap.Uint64("UserId", session.UserId()), zap.String("Reason", reason))
+
+	removeResumeToken(session.ID())
+	if data != nil {
+		session.Send(data)
+	}
+	session.Send(protos.MarshalProtoMsg(&gameProto.ClientKickS2C{
+		Reason: protos.String(reason),
+	}))
+	session.Close()
+}
+
+// 开启用户路由时同时修改Redis中记录的服务器
+func pin(session *sessions.FrontSession, serviceName string, service string) {
+	userId := session.UserId()
+	if userRouteTtl == 0 || userId == 0 {
+		return
+	}
+	err := cache.SetUserRoute(userId, serviceName, service, userRouteTtl)
+	if err != nil {
+		ERR("保存用户路由失败", zap.Uint64("UserId", userId), zap.Error(err))
+	}
+}
@@ -0,0 +1,53 @@
+package module
+
+import (
+	"github.com/yicaoyimuys/GoGameServer/core"
+	"github.com/yicaoyimuys/GoGameServer/core/config"
+	. "github.com/yicaoyimuys/GoGameServer/core/libs"
+	"github.com/yicaoyimuys/GoGameServer/core/libs/common"
+	"github.com/yicaoyimuys/GoGameServer/core/libs/protos"
+	"github.com/yicaoyimuys/GoGameServer/core/libs/sessions"
+	"github.com/yicaoyimuys/GoGameServer/core/libs/stack"
+	"github.com/yicaoyimuys/GoGameServer/core/libs/timer"
+	"github.com/yicaoyimuys/GoGameServer/servives/public/gameProto"
+	"go.uber.org/zap"
+)
+
+// StartHeartbeat 服务器定时ping客户端，根据收到回复的时间计算RTT
+func StartHeartbeat() {
+	serviceConfig := config.GetService("connector")
+	serviceNodeConfig := serviceConfig.ServiceNodes[core.Service.ID()]
+	interval := serviceNodeConfig.ServerPingInterval
+	if interval <= 0 {
+		return
+	}
+
+	timer.DoTimer(uint32(interval*1000), func() {
+		defer stack.TryError()
+
+		nowTime := common.UnixMillisecond()
+		sendMsg := protos.MarshalProtoMsg(&gameProto.ClientPingS2C{
+			Time: protos.Int64(nowTime),
+		})
+		for _, session := range sessions.FrontSessionList() {
+			session.SetServerPingTime(nowTime)
+			session.Send(sendMsg)
+		}
+	})
+	INFO("服务器Ping已开启", zap.Int("Interval", interval))
+}
+
+// Pong 使用服务器记录的发送时间计算RTT，不信任客户端返回的时间
+func Pong(session *sessions.FrontSession) {
+	session.UpdatePingTime()
+
+	pingTime := session.TakeServerPingTime()
+	if pingTime == 0 {
+		return
+	}
+	rtt := common.UnixMillisecond() - pingTime
+	if rtt < 0 {
+		return
+	}
+	session.SetRtt(rtt)
+}
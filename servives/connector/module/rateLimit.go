@@ -0,0 +1,178 @@
+package module
+
+import (
+	"encoding/json"
+	"sync"
+	"sync/atomic"
+
+	. "github.com/yicaoyimuys/GoGameServer/core/libs"
+	"github.com/yicaoyimuys/GoGameServer/core/libs/consul"
+	"github.com/yicaoyimuys/GoGameServer/core/libs/ratelimit"
+	"github.com/yicaoyimuys/GoGameServer/core/libs/sessions"
+	"github.com/yicaoyimuys/GoGameServer/core/libs/stack"
+	"github.com/yicaoyimuys/GoGameServer/core/libs/timer"
+	"github.com/yicaoyimuys/GoGameServer/servives/public/errCodes"
+	"go.uber.org/zap"
+
+	"github.com/spf13/cast"
+)
+
+// 限流规则保存在consul KV的RateLimit中，定时加载，修改后无需重启，例如:
+// [{"range":"chat","rate":2,"burst":5,"action":"drop"},{"msgId":2001,"rate":0.2,"burst":1,"action":"error"},{"rate":30,"burst":60,"action":"disconnect"}]
+const (
+	rateLimitKey = "RateLimit"
+
+	RateLimitAction_Drop       = "drop"
+	RateLimitAction_Error      = "error"
+	RateLimitAction_Disconnect = "disconnect"
+)
+
+type rateLimitRule struct {
+	MsgId     uint16  `json:"msgId"`     //指定消息ID，优先于range，每条消息只使用最具体的一条规则
+	Range     string  `json:"range"`     //消息范围：system、connector、login、game、chat，都为空时对所有消息生效
+	Rate      float64 `json:"rate"`      //每秒允许的消息数
+	Burst     float64 `json:"burst"`     //允许的突发消息数
+	Action    string  `json:"action"`    //超出限制时的处理：drop(丢弃)、error(回复错误码)、disconnect(断开连接)
+	ErrorCode int32   `json:"errorCode"` //action为error时回复的错误码，默认为RATE_LIMIT
+}
+
+func (this *rateLimitRule) key() string {
+	if this.MsgId > 0 {
+		return "msgId:" + cast.ToString(this.MsgId)
+	}
+	return "range:" + this.Range
+}
+
+func (this *rateLimitRule) match(msgId uint16, msgRange string) bool {
+	if this.MsgId > 0 {
+		return this.MsgId == msgId
+	}
+	return this.Range == "" || this.Range == msgRange
+}
+
+// 规则的优先级：msgId > range > 所有消息
+func (this *rateLimitRule) priority() int {
+	if this.MsgId > 0 {
+		return 2
+	}
+	if this.Range != "" {
+		return 1
+	}
+	return 0
+}
+
+// 匹配的规则中优先级最高的，相同时使用靠前的
+func findRateLimitRule(rules []*rateLimitRule, msgId uint16, msgRange string) *rateLimitRule {
+	var found *rateLimitRule
+	for _, rule := range rules {
+		if !rule.match(msgId, msgRange) {
+			continue
+		}
+		if found == nil || rule.priority() > found.priority() {
+			found = rule
+		}
+	}
+	return found
+}
+
+type sessionRateLimiter struct {
+	buckets map[string]*ratelimit.TokenBucket
+	rules   map[string]*rateLimitRule
+	mutex   sync.Mutex
+}
+
+var (
+	rateLimitRules  atomic.Value
+	rateLimitConfig string
+	rateLimiters    sync.Map
+)
+
+// StartRateLimit 加载限流规则，之后每10秒检测一次更新
+func StartRateLimit() {
+	loadRateLimitRules()
+	timer.DoTimer(10*1000, loadRateLimitRules)
+}
+
+func loadRateLimitRules() {
+	defer stack.TryError()
+
+	value := consul.KV_Get(rateLimitKey)
+	if value == rateLimitConfig {
+		return
+	}
+
+	rules := []*rateLimitRule{}
+	if value != "" {
+		err := json.Unmarshal([]byte(value), &rules)
+		if err != nil {
+			ERR("限流规则解析失败", zap.String("Value", value), zap.Error(err))
+			return
+		}
+	}
+	rateLimitConfig = value
+	rateLimitRules.Store(rules)
+	INFO("限流规则已更新", zap.Int("RuleNum", len(rules)))
+}
+
+// CheckRateLimit 检测消息是否超出限制，超出时按规则处理并返回false
+func CheckRateLimit(session *sessions.FrontSession, msgId uint16, msgRange string, requestSeq uint32) bool {
+	rules, _ := rateLimitRules.Load().([]*rateLimitRule)
+	if len(rules) == 0 {
+		return true
+	}
+
+	rule := findRateLimitRule(rules, msgId, msgRange)
+	if rule == nil || getRateLimiter(session).allow(rule) {
+		return true
+	}
+
+	WARN("消息超出频率限制", zap.Uint64("SessionId", session.ID()), zap.Uint16("MsgId", msgId), zap.String("Action", rule.Action))
+	if rule.Action == RateLimitAction_Error {
+		errorCode := rule.ErrorCode
+		if errorCode == 0 {
+			errorCode = errCodes.RATE_LIMIT
+		}
+		SendErrorResponse(session, requestSeq, msgId, errorCode)
+	} else if rule.Action == RateLimitAction_Disconnect {
+		//在消息处理中调用，需异步关闭
+		go session.Close()
+	}
+	return false
+}
+
+func getRateLimiter(session *sessions.FrontSession) *sessionRateLimiter {
+	sessionId := session.ID()
+	if value, ok := rateLimiters.Load(sessionId); ok {
+		return value.(*sessionRateLimiter)
+	}
+
+	limiter := &sessionRateLimiter{
+		buckets: make(map[string]*ratelimit.TokenBucket),
+		rules:   make(map[string]*rateLimitRule),
+	}
+	rateLimiters.Store(sessionId, limiter)
+	//断线重连后Session的ID会改变，两个都需删除
+	session.AddCloseCallback(nil, "rateLimit", func() {
+		rateLimiters.Delete(sessionId)
+		rateLimiters.Delete(session.ID())
+	})
+	return limiter
+}
+
+func (this *sessionRateLimiter) allow(rule *rateLimitRule) bool {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	key := rule.key()
+	bucket, ok := this.buckets[key]
+	if !ok {
+		bucket = ratelimit.NewTokenBucket(rule.Rate, rule.Burst)
+		this.buckets[key] = bucket
+		this.rules[key] = rule
+	} else if this.rules[key] != rule {
+		//规则已重新加载
+		bucket.SetRate(rule.Rate, rule.Burst)
+		this.rules[key] = rule
+	}
+	return bucket.Allow()
+}
@@ -0,0 +1,171 @@
+package module
+
+import (
+	"sync"
+
+	. "github.com/yicaoyimuys/GoGameServer/core/libs"
+	"github.com/yicaoyimuys/GoGameServer/core/libs/common"
+	"github.com/yicaoyimuys/GoGameServer/core/libs/protos"
+	"github.com/yicaoyimuys/GoGameServer/core/libs/sessions"
+	"github.com/yicaoyimuys/GoGameServer/core/libs/stack"
+	"github.com/yicaoyimuys/GoGameServer/core/libs/timer"
+	"github.com/yicaoyimuys/GoGameServer/core/messages"
+	"github.com/yicaoyimuys/GoGameServer/servives/connector/cache"
+	"github.com/yicaoyimuys/GoGameServer/servives/public/gameProto"
+	"go.uber.org/zap"
+)
+
+const (
+	//已发送未确认消息的最大数量，超出后排队等待确认
+	reliableMaxUnacked = 128
+	//排队消息的最大数量，超出后断开连接，消息保存到断线重连数据中
+	reliableMaxQueued = 1024
+	//超过该时间未确认则重发(毫秒)
+	reliableRetransmitTime = 10 * 1000
+)
+
+type reliableState struct {
+	mutex sync.Mutex
+	seq   uint32
+	msgs  []*reliableMsg
+}
+
+type reliableMsg struct {
+	seq      uint32
+	data     []byte
+	sendTime int64 //0为排队中未发送
+}
+
+var (
+	reliableStates sync.Map
+)
+
+// InitReliable 开启需确认消息的序号、重发机制
+func InitReliable() {
+	messages.SetFrontSessionReliableHandle(SendReliable)
+
+	timer.DoTimer(reliableRetransmitTime/2, func() {
+		defer stack.TryError()
+		retransmitReliable()
+	})
+}
+
+// SendReliable 为消息分配序号并保存，客户端确认后删除
+func SendReliable(session *sessions.FrontSession, data []byte) {
+	value, _ := reliableStates.LoadOrStore(session.ID(), &reliableState{})
+	state := value.(*reliableState)
+
+	state.mutex.Lock()
+	defer state.mutex.Unlock()
+
+	state.seq++
+	state.msgs = append(state.msgs, &reliableMsg{
+		seq:  state.seq,
+		data: data,
+	})
+	sendReliableWindow(session, state)
+
+	if len(state.msgs) > reliableMaxUnacked+reliableMaxQueued {
+		WARN("未确认消息过多，断开连接", zap.Uint64("SessionId", session.ID()), zap.Int("MsgNum", len(state.msgs)))
+		//可能在持有Session列表锁时调用，需异步关闭
+		go session.Close()
+	}
+}
+
+// AckReliable 客户端确认收到序号不大于seq的所有消息
+func AckReliable(session *sessions.FrontSession, seq uint32) {
+	value, ok := reliableStates.Load(session.ID())
+	if !ok {
+		return
+	}
+	state := value.(*reliableState)
+
+	state.mutex.Lock()
+	defer state.mutex.Unlock()
+
+	index := 0
+	for index < len(state.msgs) && state.msgs[index].seq <= seq {
+		index++
+	}
+	state.msgs = state.msgs[index:]
+	sendReliableWindow(session, state)
+}
+
+// 发送窗口内排队中的消息，需持有state.mutex
+func sendReliableWindow(session *sessions.FrontSession, state *reliableState) {
+	nowTime := common.UnixMillisecond()
+	for i := 0; i < len(state.msgs) && i < reliableMaxUnacked; i++ {
+		msg := state.msgs[i]
+		if msg.sendTime != 0 {
+			continue
+		}
+		msg.sendTime = nowTime
+		session.Send(packReliableMsg(msg))
+	}
+}
+
+// 断线时取出未确认的消息，重连后继续发送
+func takeReliableState(sessionId uint64) (uint32, []cache.ReliableMsg) {
+	value, ok := reliableStates.LoadAndDelete(sessionId)
+	if !ok {
+		return 0, nil
+	}
+	state := value.(*reliableState)
+
+	state.mutex.Lock()
+	defer state.mutex.Unlock()
+
+	msgs := make([]cache.ReliableMsg, len(state.msgs))
+	for i, msg := range state.msgs {
+		msgs[i] = cache.ReliableMsg{Seq: msg.seq, Data: msg.data}
+	}
+	return state.seq, msgs
+}
+
+// 重连成功后恢复序号，并重发未确认的消息
+func restoreReliableState(session *sessions.FrontSession, seq uint32, msgs []cache.ReliableMsg) {
+	state := &reliableState{seq: seq}
+	state.mutex.Lock()
+	defer state.mutex.Unlock()
+
+	reliableStates.Store(session.ID(), state)
+	for _, v := range msgs {
+		state.msgs = append(state.msgs, &reliableMsg{
+			seq:  v.Seq,
+			data: v.Data,
+		})
+	}
+	sendReliableWindow(session, state)
+}
+
+func retransmitReliable() {
+	nowTime := common.UnixMillisecond()
+	reliableStates.Range(func(key, value interface{}) bool {
+		session := sessions.GetFrontSession(key.(uint64))
+		if session == nil {
+			return true
+		}
+		state := value.(*reliableState)
+
+		state.mutex.Lock()
+		defer state.mutex.Unlock()
+
+		//最早的消息超时未确认，重发窗口内的消息，客户端根据序号去重
+		if len(state.msgs) == 0 || nowTime-state.msgs[0].sendTime < reliableRetransmitTime {
+			return true
+		}
+		for i := 0; i < len(state.msgs) && i < reliableMaxUnacked; i++ {
+			msg := state.msgs[i]
+			msg.sendTime = nowTime
+			session.Send(packReliableMsg(msg))
+		}
+		return true
+	})
+}
+
+func packReliableMsg(msg *reliableMsg) []byte {
+	return protos.MarshalProtoMsg(&gameProto.ClientReliableS2C{
+		Seq:  protos.Uint32(msg.seq),
+		Data: msg.data,
+	})
+}
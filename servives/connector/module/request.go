@@ -0,0 +1,50 @@
+package module
+
+import (
+	"github.com/yicaoyimuys/GoGameServer/core/libs/protos"
+	"github.com/yicaoyimuys/GoGameServer/core/libs/sessions"
+	"github.com/yicaoyimuys/GoGameServer/core/messages"
+	"github.com/yicaoyimuys/GoGameServer/servives/public/gameProto"
+)
+
+// InitRequest 后端服务器对带序号请求的回复，带上序号发送给客户端
+func InitRequest() {
+	messages.SetResponsePackHandle(PackResponse)
+}
+
+// UnpackRequest 取出请求序号及原消息，格式错误时返回nil
+func UnpackRequest(msgBody []byte) (uint32, []byte) {
+	protoMsg := protos.UnmarshalProtoMsg(msgBody)
+	if protoMsg == protos.NullProtoMsg {
+		return 0, nil
+	}
+	data := protoMsg.Body.(*gameProto.ClientRequestC2S)
+	if len(data.GetData()) < 2 {
+		return 0, nil
+	}
+	return data.GetSeq(), data.GetData()
+}
+
+func PackResponse(requestSeq uint32, data []byte) []byte {
+	return protos.MarshalProtoMsg(&gameProto.ClientResponseS2C{
+		Seq:  protos.Uint32(requestSeq),
+		Data: data,
+	})
+}
+
+// SendResponse connector直接回复客户端的请求，requestSeq为0时不带序号
+func SendResponse(session *sessions.FrontSession, requestSeq uint32, data []byte) {
+	if requestSeq != 0 {
+		data = PackResponse(requestSeq, data)
+	}
+	session.Send(data)
+}
+
+// SendErrorResponse 回复错误码及出错的消息ID
+func SendErrorResponse(session *sessions.FrontSession, requestSeq uint32, msgId uint16, errorCode int32) {
+	sendMsg := protos.MarshalProtoMsg(&gameProto.ErrorNoticeS2C{
+		ErrorCode: protos.Int32(errorCode),
+		MsgId:     protos.Uint32(uint32(msgId)),
+	})
+	SendResponse(session, requestSeq, sendMsg)
+}
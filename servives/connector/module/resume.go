@@ -0,0 +1,343 @@
+package module
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/yicaoyimuys/GoGameServer/core"
+	"github.com/yicaoyimuys/GoGameServer/core/config"
+	. "github.com/yicaoyimuys/GoGameServer/core/libs"
+	"github.com/yicaoyimuys/GoGameServer/core/libs/grpc/ipc"
+	"github.com/yicaoyimuys/GoGameServer/core/libs/protos"
+	"github.com/yicaoyimuys/GoGameServer/core/libs/sessions"
+	"github.com/yicaoyimuys/GoGameServer/core/libs/stack"
+	"github.com/yicaoyimuys/GoGameServer/core/libs/timer"
+	"github.com/yicaoyimuys/GoGameServer/core/messages"
+	"github.com/yicaoyimuys/GoGameServer/servives/connector/cache"
+	"github.com/yicaoyimuys/GoGameServer/servives/public/gameProto"
+	"go.uber.org/zap"
+)
+
+const (
+	//断线期间最多缓存的消息数
+	resumeMsgMaxLen = 256
+	//重连Token的随机字节数
+	resumeTokenSize = 16
+
+	//等待原connector缓存完切换前消息的最长时间
+	resumeMovedTimeout = time.Second
+
+	//等待原connector保存断线Session的重试间隔
+	resumeRetryInterval = 100 * time.Millisecond
+	//未配置心跳时的默认超时时间及检测间隔(秒)，与前端服务器一致
+	defaultPingTimeout  = 15
+	defaultPingInterval = 2
+)
+
+var (
+	resumeGrace time.Duration
+	//原connector最迟在心跳超时后检测到断开，超过该时间不再等待
+	resumeWaitTimeout time.Duration
+
+	//在线Session的重连Token
+	resumeTokens     = make(map[uint64]string)
+	resumeSessionIds = make(map[string]uint64)
+	resumeMutex      sync.Mutex
+
+	//断线等待重连的Session
+	detachedSessions sync.Map
+)
+
+// InitResume 开启断线重连，需在StartFront之前调用
+func InitResume() {
+	serviceConfig := config.GetService("connector")
+	serviceNodeConfig := serviceConfig.ServiceNodes[core.Service.ID()]
+	if serviceNodeConfig.ResumeGrace <= 0 {
+		return
+	}
+	resumeGrace = time.Duration(serviceNodeConfig.ResumeGrace) * time.Second
+
+	pingTimeout := serviceNodeConfig.PingTimeout
+	if pingTimeout <= 0 {
+		pingTimeout = defaultPingTimeout
+	}
+	pingInterval := serviceNodeConfig.PingInterval
+	if pingInterval <= 0 {
+		pingInterval = defaultPingInterval
+	}
+	resumeWaitTimeout = time.Duration(pingTimeout+pingInterval) * time.Second
+	if resumeWaitTimeout > resumeGrace {
+		resumeWaitTimeout = resumeGrace
+	}
+
+	messages.SetFrontSessionMissHandle(frontSessionMissHandle)
+	messages.SetFrontSessionMovedHandle(frontSessionMovedHandle)
+	INFO("断线重连已开启", zap.Duration("Grace", resumeGrace))
+}
+
+// ServiceIdentify 后端服务器中该Session所属的connector
+func ServiceIdentify(session *sessions.FrontSession) string {
+	serviceIdentify := session.ServiceIdentify()
+	if serviceIdentify == "" {
+		serviceIdentify = core.Service.Identify()
+	}
+	return serviceIdentify
+}
+
+// FrontSessionCreate 连接建立时下发重连Token
+func FrontSessionCreate(session *sessions.FrontSession) {
+	if resumeGrace == 0 {
+		return
+	}
+	sendResumeToken(session)
+}
+
+// FrontSessionClose 断线后保留后端服务器中的Session，超时未重连再通知下线
+func FrontSessionClose(session *sessions.FrontSession, offline func()) {
+	sessionId := session.ID()
+	reliableSeq, reliableMsgs := takeReliableState(sessionId)
+	token := removeResumeToken(sessionId)
+	if token == "" {
+		offline()
+		return
+	}
+
+	resumeSession := &cache.ResumeSession{
+		Connector:       core.Service.Identify(),
+		ServiceIdentify: ServiceIdentify(session),
+		SessionId:       sessionId,
+		UserId:          session.UserId(),
+		IpcServices:     session.GetIpcServices(),
+		Groups:          sessions.FrontSessionGroups(session),
+		Attrs:           session.Attrs(),
+		ReliableSeq:     reliableSeq,
+		ReliableMsgs:    reliableMsgs,
+	}
+	//Redis中的数据多保留一段时间，由超时处理负责删除
+	err := cache.SetResumeSession(token, resumeSession, resumeGrace*2)
+	if err != nil {
+		ERR("保存断线Session失败", zap.Error(err))
+		offline()
+		return
+	}
+	detachedSessions.Store(sessionId, token)
+
+	timer.SetTimeOut(uint32(resumeGrace/time.Millisecond), func() {
+		defer stack.TryError()
+
+		if value, ok := detachedSessions.Load(sessionId); ok && value.(string) == token {
+			detachedSessions.Delete(sessionId)
+		}
+
+		//已在其他连接重连成功
+		if cache.TakeResumeSession(token) == nil {
+			return
+		}
+		cache.DelResumeMsgs(sessionId)
+		cache.DelResumeMoved(sessionId)
+		offline()
+	})
+}
+
+// Resume 新连接绑定到断线前的Session，并补发断线期间的消息
+func Resume(session *sessions.FrontSession, token string) {
+	//Token格式错误时不等待原connector
+	if resumeGrace == 0 || len(token) != hex.EncodedLen(resumeTokenSize) {
+		sendResumeResult(session, false)
+		return
+	}
+
+	//新连接已经与后端服务器交互过，不能再绑定其他Session
+	if len(session.GetIpcServices()) > 0 {
+		sendResumeResult(session, false)
+		return
+	}
+
+	//原连接还未检测到断开，先关闭原连接
+	if oldSessionId := getResumeSessionId(token); oldSessionId != 0 {
+		oldSession := sessions.GetFrontSession(oldSessionId)
+		if oldSession != nil && oldSession != session {
+			oldSession.Close()
+		}
+	}
+
+	resumeSession := takeResumeSession(session, token)
+	if resumeSession == nil {
+		sendResumeResult(session, false)
+		return
+	}
+
+	//补发断线期间的消息前，新收到的消息先暂存
+	tempSessionId := session.ID()
+	messages.HoldFrontSession(resumeSession.SessionId, tempSessionId)
+
+	//使用原Session的ID
+	removeResumeToken(session.ID())
+	takeReliableState(session.ID())
+	sessions.RebindFrontSession(session, resumeSession.SessionId)
+	session.SetServiceIdentify(resumeSession.ServiceIdentify)
+	session.SetUserId(resumeSession.UserId)
+	for _, group := range resumeSession.Groups {
+		sessions.JoinFrontGroup(group, session)
+	}
+	for key, value := range resumeSession.Attrs {
+		session.SetAttr(key, value)
+	}
+	detachedSessions.Delete(resumeSession.SessionId)
+
+	//通知后端服务器之后的消息发送到本connector
+	movedNum := 0
+	for serviceName, service := range resumeSession.IpcServices {
+		session.SetIpcService(serviceName, service)
+
+		ipcClient := core.Service.GetIpcClient(serviceName)
+		if ipcClient == nil {
+			continue
+		}
+		err := ipcClient.SendReq(&ipc.Req{
+			ServiceIdentify: resumeSession.ServiceIdentify,
+			UserSessionId:   resumeSession.SessionId,
+			RemoteAddr:      session.RemoteAddr(),
+			UserId:          session.UserId(),
+		}, service)
+		if err != nil {
+			ERR("断线重连绑定失败", zap.String("Service", service), zap.Error(err))
+			continue
+		}
+		movedNum++
+	}
+
+	//在其他connector断线时，等待原connector缓存完切换前后端服务器发送的消息
+	if resumeSession.Connector != core.Service.Identify() {
+		waitResumeMoved(resumeSession.SessionId, movedNum)
+	}
+	msgs := cache.TakeResumeMsgs(resumeSession.SessionId)
+	cache.DelResumeMoved(resumeSession.SessionId)
+
+	//重发断线前未确认的消息，再补发断线期间的消息，之后再发送暂存的消息
+	messages.ReleaseFrontSession(func() {
+		sendResumeResult(session, true)
+		sendResumeToken(session)
+
+		restoreReliableState(session, resumeSession.ReliableSeq, resumeSession.ReliableMsgs)
+		for _, msg := range msgs {
+			if msg.Reliable {
+				SendReliable(session, msg.Data)
+			} else {
+				session.Send(msg.Data)
+			}
+		}
+	}, resumeSession.SessionId, tempSessionId)
+	DEBUG("断线重连成功", zap.Uint64("SessionId", resumeSession.SessionId), zap.Int("MsgNum", len(msgs)))
+}
+
+// 在其他connector断线时，原connector可能还未检测到断开并保存Session，超时前一直重试
+func takeResumeSession(session *sessions.FrontSession, token string) *cache.ResumeSession {
+	deadline := time.Now().Add(resumeWaitTimeout)
+	for !session.IsClosed() {
+		resumeSession := cache.TakeResumeSession(token)
+		if resumeSession != nil {
+			return resumeSession
+		}
+		if time.Now().After(deadline) {
+			return nil
+		}
+		time.Sleep(resumeRetryInterval)
+	}
+	return nil
+}
+
+// 原connector每收到一个后端服务器的切换通知计数一次，超时后不再等待
+func waitResumeMoved(sessionId uint64, movedNum int) {
+	deadline := time.Now().Add(resumeMovedTimeout)
+	for cache.GetResumeMoved(sessionId) < movedNum {
+		if time.Now().After(deadline) {
+			WARN("等待原connector切换超时", zap.Uint64("SessionId", sessionId), zap.Int("MovedNum", movedNum))
+			return
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+// 后端服务器已切换到其他connector，之前发送的消息都已缓存
+func frontSessionMovedHandle(userSessionId uint64) {
+	if _, ok := detachedSessions.Load(userSessionId); !ok {
+		return
+	}
+	err := cache.AddResumeMoved(userSessionId, resumeGrace*2)
+	if err != nil {
+		ERR("记录断线Session切换失败", zap.Error(err))
+	}
+}
+
+// 断线期间发送给客户端的消息缓存到Redis，重连后补发
+func frontSessionMissHandle(userSessionId uint64, data []byte, reliable bool) bool {
+	msg := &cache.ResumeMsg{
+		Data:     data,
+		Reliable: reliable,
+	}
+	if userSessionId == 0 {
+		detachedSessions.Range(func(key, value interface{}) bool {
+			cache.AddResumeMsg(key.(uint64), msg, resumeMsgMaxLen, resumeGrace*2)
+			return true
+		})
+		return true
+	}
+
+	if _, ok := detachedSessions.Load(userSessionId); !ok {
+		return false
+	}
+	err := cache.AddResumeMsg(userSessionId, msg, resumeMsgMaxLen, resumeGrace*2)
+	if err != nil {
+		ERR("缓存断线消息失败", zap.Error(err))
+	}
+	return true
+}
+
+func sendResumeToken(session *sessions.FrontSession) {
+	token := newResumeToken()
+
+	resumeMutex.Lock()
+	resumeTokens[session.ID()] = token
+	resumeSessionIds[token] = session.ID()
+	resumeMutex.Unlock()
+
+	sendMsg := protos.MarshalProtoMsg(&gameProto.ClientResumeTokenS2C{
+		Token: protos.String(token),
+	})
+	session.Send(sendMsg)
+}
+
+func sendResumeResult(session *sessions.FrontSession, success bool) {
+	sendMsg := protos.MarshalProtoMsg(&gameProto.ClientResumeS2C{
+		Success: protos.Bool(success),
+	})
+	session.Send(sendMsg)
+}
+
+func removeResumeToken(sessionId uint64) string {
+	resumeMutex.Lock()
+	defer resumeMutex.Unlock()
+
+	token, ok := resumeTokens[sessionId]
+	if ok {
+		delete(resumeTokens, sessionId)
+		delete(resumeSessionIds, token)
+	}
+	return token
+}
+
+func getResumeSessionId(token string) uint64 {
+	resumeMutex.Lock()
+	defer resumeMutex.Unlock()
+
+	return resumeSessionIds[token]
+}
+
+func newResumeToken() string {
+	buf := make([]byte, resumeTokenSize)
+	rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
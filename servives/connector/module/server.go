@@ -27,7 +27,7 @@ func initServerLogTimer() {
 		}
 
 		ip := core.Service.Ip()
-		port := core.Service.Port(consts.ServiceType_Socket)
+		port := getClientPort()
 		if ip == "" || port == "" {
 			ERR("Invalid ip or port", zap.String("ip", ip), zap.String("port", port))
 			return
@@ -37,3 +37,15 @@ func initServerLogTimer() {
 		INFO("当前在线用户数量", zap.Int("OnlineUsersNum", onlineUsersNum))
 	})
 }
+
+// 获取本进程启用的前端端口
+func getClientPort() string {
+	clientTypes := []string{consts.ServiceType_Socket, consts.ServiceType_WebSocket, consts.ServiceType_Kcp}
+	for _, clientType := range clientTypes {
+		port := core.Service.Port(clientType)
+		if port != "" {
+			return port
+		}
+	}
+	return ""
+}
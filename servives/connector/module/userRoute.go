@@ -0,0 +1,90 @@
+package module
+
+import (
+	"time"
+
+	"github.com/yicaoyimuys/GoGameServer/core"
+	"github.com/yicaoyimuys/GoGameServer/core/config"
+	. "github.com/yicaoyimuys/GoGameServer/core/libs"
+	"github.com/yicaoyimuys/GoGameServer/core/libs/sessions"
+	"github.com/yicaoyimuys/GoGameServer/core/libs/stack"
+	"github.com/yicaoyimuys/GoGameServer/core/libs/timer"
+	"github.com/yicaoyimuys/GoGameServer/servives/connector/cache"
+	"go.uber.org/zap"
+)
+
+const (
+	//Redis中用户所在服务器的默认保留时间(秒)
+	defaultUserRouteTtl = 300
+)
+
+var (
+	userRouteTtl time.Duration
+)
+
+// InitUserRoute 用户所在服务器记录到Redis，在线期间定时续期，需在StartFront之前调用
+func InitUserRoute() {
+	serviceConfig := config.GetService("connector")
+	serviceNodeConfig := serviceConfig.ServiceNodes[core.Service.ID()]
+	ttl := serviceNodeConfig.UserRouteTtl
+	if ttl <= 0 {
+		ttl = defaultUserRouteTtl
+	}
+	userRouteTtl = time.Duration(ttl) * time.Second
+
+	timer.DoTimer(uint32(ttl*1000/3), func() {
+		defer stack.TryError()
+
+		for _, session := range sessions.FrontSessionList() {
+			userId := session.UserId()
+			if userId == 0 {
+				continue
+			}
+			err := cache.RefreshUserRoute(userId, userRouteTtl)
+			if err != nil {
+				ERR("用户路由续期失败", zap.Uint64("UserId", userId), zap.Error(err))
+			}
+		}
+	})
+	INFO("用户路由已开启", zap.Duration("Ttl", userRouteTtl))
+}
+
+// UserRouteService 返回Redis中记录的用户所在服务器，未记录或已下线时记录为pick分配的服务器
+// pick只在需要分配时调用，services为当前可用的服务器，Redis不可用时返回错误，由调用方按一致性哈希分配
+func UserRouteService(userId uint64, serviceName string, pick func() string, services map[string]int) (string, error) {
+	if userRouteTtl == 0 || userId == 0 {
+		return pick(), nil
+	}
+
+	record, err := cache.GetUserRoute(userId, serviceName)
+	if err != nil {
+		return "", err
+	}
+	if record != "" {
+		if _, ok := services[record]; ok {
+			return record, nil
+		}
+	}
+
+	service := pick()
+	if service == "" {
+		return "", nil
+	}
+	if record != "" {
+		//原服务器已下线
+		err := cache.SetUserRoute(userId, serviceName, service, userRouteTtl)
+		if err != nil {
+			ERR("保存用户路由失败", zap.Uint64("UserId", userId), zap.Error(err))
+		}
+		return service, nil
+	}
+
+	record, err = cache.AddUserRoute(userId, serviceName, service, userRouteTtl)
+	if err != nil {
+		return "", err
+	}
+	if _, ok := services[record]; !ok {
+		return service, nil
+	}
+	return record, nil
+}
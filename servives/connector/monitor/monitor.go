@@ -1 +1,126 @@
- 
\ No newline at end of file
+package monitor
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"time"
+
+	. "github.com/yicaoyimuys/GoGameServer/core/libs"
+	"github.com/yicaoyimuys/GoGameServer/core/libs/sessions"
+	"github.com/yicaoyimuys/GoGameServer/core/libs/stack"
+	"github.com/yicaoyimuys/GoGameServer/core/libs/timer"
+	"go.uber.org/zap"
+)
+
+const (
+	//RTT最高的Session显示数量
+	slowSessionNum = 10
+	//超过该时间未收到消息视为空闲(秒)
+	idleTime = 5
+)
+
+type SessionStat struct {
+	SessionId uint64 `json:"sessionId"`
+	Rtt       int64  `json:"rtt"`
+	Idle      int64  `json:"idle"`
+}
+
+// SessionStats Session的RTT(毫秒)、空闲时间(秒)统计
+type SessionStats struct {
+	SessionNum   int            `json:"sessionNum"`
+	RttNum       int            `json:"rttNum"`
+	RttAvg       int64          `json:"rttAvg"`
+	RttP50       int64          `json:"rttP50"`
+	RttP95       int64          `json:"rttP95"`
+	RttP99       int64          `json:"rttP99"`
+	RttMax       int64          `json:"rttMax"`
+	IdleNum      int            `json:"idleNum"`
+	IdleMax      int64          `json:"idleMax"`
+	SlowSessions []*SessionStat `json:"slowSessions"`
+}
+
+// Start 每分钟记录一次统计，同时可通过PProf端口的/monitor/sessions查看，/monitor/route/moved用于扩缩容预览
+func Start() {
+	http.HandleFunc("/monitor/sessions", statsHandler)
+	http.HandleFunc("/monitor/route/moved", routeMovedHandler)
+
+	timer.DoTimer(60*1000, func() {
+		defer stack.TryError()
+
+		stats := GetSessionStats()
+		INFO("Session统计", zap.Int("SessionNum", stats.SessionNum), zap.Int("RttNum", stats.RttNum),
+			zap.Int64("RttAvg", stats.RttAvg), zap.Int64("RttP95", stats.RttP95), zap.Int64("RttMax", stats.RttMax),
+			zap.Int("IdleNum", stats.IdleNum), zap.Int64("IdleMax", stats.IdleMax))
+	})
+}
+
+func GetSessionStats() *SessionStats {
+	nowTime := time.Now().Unix()
+	list := sessions.FrontSessionList()
+
+	stats := &SessionStats{
+		SessionNum:   len(list),
+		SlowSessions: []*SessionStat{},
+	}
+	rtts := make([]int64, 0, len(list))
+	sessionStats := make([]*SessionStat, 0, len(list))
+	var rttTotal int64
+	for _, session := range list {
+		idle := nowTime - session.PingTime()
+		if idle >= idleTime {
+			stats.IdleNum++
+		}
+		if idle > stats.IdleMax {
+			stats.IdleMax = idle
+		}
+
+		rtt := session.Rtt()
+		if rtt < 0 {
+			continue
+		}
+		rtts = append(rtts, rtt)
+		rttTotal += rtt
+		sessionStats = append(sessionStats, &SessionStat{
+			SessionId: session.ID(),
+			Rtt:       rtt,
+			Idle:      idle,
+		})
+	}
+
+	stats.RttNum = len(rtts)
+	if stats.RttNum == 0 {
+		return stats
+	}
+	sort.Slice(rtts, func(i, j int) bool {
+		return rtts[i] < rtts[j]
+	})
+	stats.RttAvg = rttTotal / int64(stats.RttNum)
+	stats.RttP50 = percentile(rtts, 50)
+	stats.RttP95 = percentile(rtts, 95)
+	stats.RttP99 = percentile(rtts, 99)
+	stats.RttMax = rtts[len(rtts)-1]
+
+	sort.Slice(sessionStats, func(i, j int) bool {
+		return sessionStats[i].Rtt > sessionStats[j].Rtt
+	})
+	if len(sessionStats) > slowSessionNum {
+		sessionStats = sessionStats[:slowSessionNum]
+	}
+	stats.SlowSessions = sessionStats
+	return stats
+}
+
+func percentile(sorted []int64, p int) int64 {
+	index := len(sorted) * p / 100
+	if index >= len(sorted) {
+		index = len(sorted) - 1
+	}
+	return sorted[index]
+}
+
+func statsHandler(w http.ResponseWriter, r *http.Request) {
+	data, _ := json.Marshal(GetSessionStats())
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+}
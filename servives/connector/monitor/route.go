@@ -0,0 +1,95 @@
+package monitor
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/yicaoyimuys/GoGameServer/core"
+	"github.com/yicaoyimuys/GoGameServer/core/libs/hash"
+
+	"github.com/spf13/cast"
+)
+
+const (
+	//Body最大长度
+	routeMovedMaxBody = 8 << 20
+	//一次最多检查的Key数量
+	routeMovedMaxKeys = 100000
+)
+
+// RouteMoved 扩缩容预览结果
+type RouteMoved struct {
+	Service       string          `json:"service"`
+	Weights       map[string]int  `json:"weights"`
+	TargetWeights map[string]int  `json:"targetWeights"`
+	KeyNum        int             `json:"keyNum"`
+	MovedKeys     []hash.MovedKey `json:"movedKeys"`
+}
+
+// 扩缩容前查看会重新分配服务器的Key，例如：
+// POST /monitor/route/moved?service=game&add=10.0.0.3:3001@2&remove=10.0.0.1:3001，Body中每行一个Key
+// 也可使用keys参数传入以逗号分隔的Key
+func routeMovedHandler(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	serviceName := query.Get("service")
+	if core.Service == nil || core.Service.GetIpcClient(serviceName) == nil {
+		http.Error(w, "service not exists", http.StatusBadRequest)
+		return
+	}
+	ipcClient := core.Service.GetIpcClient(serviceName)
+
+	//目标服务列表
+	weights := ipcClient.GetServiceWeights()
+	targetWeights := ipcClient.GetServiceWeights()
+	for _, value := range query["add"] {
+		arr := strings.SplitN(value, "@", 2)
+		weight := 1
+		if len(arr) == 2 {
+			weight = cast.ToInt(arr[1])
+		}
+		targetWeights[arr[0]] = weight
+	}
+	for _, value := range query["remove"] {
+		delete(targetWeights, value)
+	}
+
+	//需检查的Key
+	keys := []string{}
+	for _, key := range strings.Split(query.Get("keys"), ",") {
+		if key = strings.TrimSpace(key); key != "" {
+			keys = append(keys, key)
+		}
+	}
+	if r.Method == http.MethodPost {
+		scanner := bufio.NewScanner(http.MaxBytesReader(w, r.Body, routeMovedMaxBody))
+		for scanner.Scan() {
+			if key := strings.TrimSpace(scanner.Text()); key != "" {
+				keys = append(keys, key)
+			}
+			if len(keys) > routeMovedMaxKeys {
+				break
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+			return
+		}
+	}
+	if len(keys) > routeMovedMaxKeys {
+		http.Error(w, "too many keys", http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	result := &RouteMoved{
+		Service:       serviceName,
+		Weights:       weights,
+		TargetWeights: targetWeights,
+		KeyNum:        len(keys),
+		MovedKeys:     ipcClient.GetMovedKeys(keys, targetWeights),
+	}
+	data, _ := json.Marshal(result)
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+}
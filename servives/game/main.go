@@ -6,6 +6,7 @@ import (
 	"github.com/yicaoyimuys/GoGameServer/core/messages"
 	"github.com/yicaoyimuys/GoGameServer/core/service"
 	"github.com/yicaoyimuys/GoGameServer/servives/game/module"
+	"github.com/yicaoyimuys/GoGameServer/servives/public"
 	"github.com/yicaoyimuys/GoGameServer/servives/public/gameProto"
 )
 
@@ -29,7 +30,12 @@ func main() {
 }
 
 func initMessage() {
-	messages.RegisterIpcServerHandle(gameProto.ID_user_getInfo_c2s, module.GetInfo)
+	//所有消息的中间件
+	public.UseDefaultMiddleware()
+	//消息处理返回错误时回复客户端错误码
+	messages.SetIpcServerErrorHandle(public.ReplyErrorToClient)
+
+	messages.RegisterIpcServerRequest(gameProto.ID_user_getInfo_c2s, module.GetInfo, messages.RequireLogin())
 }
 
 func initModule() {
@@ -2,30 +2,18 @@ package module
 
 import (
 	"github.com/yicaoyimuys/GoGameServer/core/libs/protos"
-	"github.com/yicaoyimuys/GoGameServer/core/libs/sessions"
-	"github.com/yicaoyimuys/GoGameServer/servives/public"
+	"github.com/yicaoyimuys/GoGameServer/core/messages"
 	"github.com/yicaoyimuys/GoGameServer/servives/public/errCodes"
 	"github.com/yicaoyimuys/GoGameServer/servives/public/gameProto"
 	"github.com/yicaoyimuys/GoGameServer/servives/public/redisCaches"
-
-	"google.golang.org/protobuf/proto"
 )
 
 // 获取用户信息
-func GetInfo(clientSession *sessions.BackSession, msgData proto.Message) {
-	data := msgData.(*gameProto.UserGetInfoC2S)
-	token := data.GetToken()
-	userId := public.GetUserIdByToken(token)
-	if userId == 0 {
-		public.SendErrorMsgToClient(clientSession, errCodes.PARAM_ERROR)
-		return
-	}
-
-	//获取缓存中用户数据
-	dbUser := redisCaches.GetUser(userId)
+func GetInfo(ctx *messages.Context, req *gameProto.UserGetInfoC2S) (*gameProto.UserGetInfoS2C, error) {
+	//已由RequireLogin检查登录
+	dbUser := redisCaches.GetUser(ctx.UserID())
 	if dbUser == nil {
-		public.SendErrorMsgToClient(clientSession, errCodes.PARAM_ERROR)
-		return
+		return nil, errCodes.ErrParam
 	}
 
 	//返回客户端消息
@@ -36,5 +24,5 @@ func GetInfo(clientSession *sessions.BackSession, msgData proto.Message) {
 			Money: protos.Int32(dbUser.Money),
 		},
 	}
-	public.SendMsgToClient(clientSession, sendMsg)
+	return sendMsg, nil
 }
@@ -7,6 +7,7 @@ import (
 	"github.com/yicaoyimuys/GoGameServer/core/libs/protos"
 	"github.com/yicaoyimuys/GoGameServer/core/libs/random"
 	"github.com/yicaoyimuys/GoGameServer/core/libs/sessions"
+	"github.com/yicaoyimuys/GoGameServer/core/messages"
 	"github.com/yicaoyimuys/GoGameServer/servives/login/cache"
 	"github.com/yicaoyimuys/GoGameServer/servives/public"
 	"github.com/yicaoyimuys/GoGameServer/servives/public/gameProto"
@@ -18,27 +19,35 @@ import (
 )
 
 // 登录
-func Login(clientSession *sessions.BackSession, msgData proto.Message) {
-	data := msgData.(*gameProto.UserLoginC2S)
-	account := data.GetAccount()
+func Login(ctx *messages.Context, req *gameProto.UserLoginC2S) (*gameProto.UserLoginS2C, error) {
+	clientSession := ctx.Session
+	account := req.GetAccount()
 
 	onlineUser := cache.GetOnlineUserByAccount(account)
 	if onlineUser != nil {
 		oldClientSession := onlineUser.Session
-		if oldClientSession.ID() != clientSession.ID() {
-			//当前在线，但是连接不同，其他客户端连接，需通知当前客户端下线
-			sendOtherLogin(oldClientSession)
-			//替换Session
-			cache.RemoveOnlineUser(oldClientSession.ID())
-			//登录成功后处理
-			loginSuccess(clientSession, onlineUser.Account, onlineUser.UserID)
+		if oldClientSession.ID() == clientSession.ID() {
+			//同一连接重复登录，返回新的Token
+			return loginResponse(onlineUser.UserID), nil
 		}
-	} else {
-		//进行DB登录
-		dbUser := login(account)
+		//当前在线，但是连接不同，其他客户端连接，需通知当前客户端下线
+		sendOtherLogin(oldClientSession)
+		//替换Session
+		cache.RemoveOnlineUser(oldClientSession.ID())
 		//登录成功后处理
-		loginSuccess(clientSession, dbUser.Account, dbUser.Id)
+		return loginSuccess(clientSession, onlineUser.Account, onlineUser.UserID), nil
 	}
+
+	//进行DB登录
+	dbUser := login(account)
+	//登录成功后处理
+	return loginSuccess(clientSession, dbUser.Account, dbUser.Id), nil
+}
+
+// CheckLogin 账号不能为空
+func CheckLogin(msgData proto.Message) bool {
+	data := msgData.(*gameProto.UserLoginC2S)
+	return data.GetAccount() != ""
 }
 
 func login(account string) *mysqlModels.User {
@@ -59,24 +68,30 @@ func login(account string) *mysqlModels.User {
 }
 
 // 登录成功后处理
-func loginSuccess(clientSession *sessions.BackSession, account string, userID uint64) {
+func loginSuccess(clientSession *sessions.BackSession, account string, userID uint64) *gameProto.UserLoginS2C {
 	//缓存用户在线数据
 	cache.AddOnlineUser(userID, account, clientSession)
+	clientSession.BindUser(userID)
 	clientSession.AddCloseCallback(nil, "user.loginSuccess", func() {
 		cache.RemoveOnlineUser(clientSession.ID())
 		DEBUG("用户下线", zap.Int32("OnlineUsersNum", cache.GetOnlineUsersNum()))
 	})
 	DEBUG("用户上线", zap.Int32("OnlineUsersNum", cache.GetOnlineUsersNum()))
+	INFO("用户登录", zap.String("Account", account), zap.Uint64("UserId", userID), zap.String("RemoteAddr", clientSession.RemoteAddr()))
+
+	return loginResponse(userID)
+}
 
-	//返回客户端数据
+// 返回客户端数据
+func loginResponse(userID uint64) *gameProto.UserLoginS2C {
 	token := public.CreateToken(userID)
-	sendMsg := &gameProto.UserLoginS2C{
+	return &gameProto.UserLoginS2C{
 		Token: protos.String(token),
 	}
-	public.SendMsgToClient(clientSession, sendMsg)
 }
 
+// 通知原客户端后断开连接
 func sendOtherLogin(clientSession *sessions.BackSession) {
 	sendMsg := &gameProto.UserOtherLoginNoticeS2C{}
-	public.SendMsgToClient(clientSession, sendMsg)
+	public.KickClient(clientSession, "otherLogin", sendMsg)
 }
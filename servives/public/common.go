@@ -9,11 +9,25 @@ import (
 	"google.golang.org/protobuf/proto"
 )
 
+// SendErrorMsgToClient 回复当前处理中消息的错误码，只能在消息处理协程中、处理返回前调用
 func SendErrorMsgToClient(session *sessions.BackSession, errorCode int32) {
+	if session == nil {
+		return
+	}
 	sendMsg := &gameProto.ErrorNoticeS2C{
 		ErrorCode: protos.Int32(errorCode),
+		MsgId:     protos.Uint32(uint32(session.RequestMsgId())),
 	}
-	SendMsgToClient(session, sendMsg)
+	ReplyMsgToClient(session, sendMsg)
+}
+
+// ReplyMsgToClient 回复当前处理中的消息，客户端请求带有序号时回复中带上相同的序号
+// 只能在消息处理协程中、处理返回前调用，异步回复使用messages.Context.Reply
+func ReplyMsgToClient(session *sessions.BackSession, sendMsg proto.Message) {
+	if session == nil || sendMsg == nil {
+		return
+	}
+	session.Reply(protos.MarshalProtoMsg(sendMsg))
 }
 
 func SendMsgToClient(session *sessions.BackSession, sendMsg proto.Message) {
@@ -23,6 +37,14 @@ func SendMsgToClient(session *sessions.BackSession, sendMsg proto.Message) {
 	session.Send(protos.MarshalProtoMsg(sendMsg))
 }
 
+// SendReliableMsgToClient 重要消息(奖励、邮件等)使用，客户端确认前connector会保留并重发
+func SendReliableMsgToClient(session *sessions.BackSession, sendMsg proto.Message) {
+	if session == nil || sendMsg == nil {
+		return
+	}
+	session.SendReliable(protos.MarshalProtoMsg(sendMsg))
+}
+
 func SendMsgToClientList(userSessionIds []uint64, sendMsg proto.Message) {
 	data := protos.MarshalProtoMsg(sendMsg)
 	core.Service.GetIpcServer().SendToAllClient(userSessionIds, data)
@@ -32,3 +54,44 @@ func SendMsgToAllClient(sendMsg proto.Message) {
 	data := protos.MarshalProtoMsg(sendMsg)
 	core.Service.GetIpcServer().SendToAllClient(nil, data)
 }
+
+// KickClient connector发送sendMsg(可为nil)后断开客户端连接
+func KickClient(session *sessions.BackSession, reason string, sendMsg proto.Message) {
+	if session == nil {
+		return
+	}
+	var data []byte
+	if sendMsg != nil {
+		data = protos.MarshalProtoMsg(sendMsg)
+	}
+	session.Kick(reason, data)
+}
+
+// ForwardMsgToService 代替客户端发送消息给其他后端服务，对方的回复直接发送给客户端
+func ForwardMsgToService(session *sessions.BackSession, serviceName string, sendMsg proto.Message) {
+	if session == nil || sendMsg == nil {
+		return
+	}
+	session.Forward(serviceName, "", protos.MarshalProtoMsg(sendMsg))
+}
+
+// JoinGroup 加入connector中的分组(房间、公会、世界频道等)，Session断开后自动离开
+func JoinGroup(session *sessions.BackSession, group string) {
+	if session == nil {
+		return
+	}
+	session.JoinGroup(group)
+}
+
+func LeaveGroup(session *sessions.BackSession, group string) {
+	if session == nil {
+		return
+	}
+	session.LeaveGroup(group)
+}
+
+// SendMsgToGroup 每个connector只发送一次，由connector转发给分组中的所有Session
+func SendMsgToGroup(group string, sendMsg proto.Message) {
+	data := protos.MarshalProtoMsg(sendMsg)
+	core.Service.GetIpcServer().PublishToGroup(group, data)
+}
@@ -2,4 +2,6 @@ package errCodes
 
 const (
 	PARAM_ERROR = 1 //参数错误
+	RATE_LIMIT  = 2 //请求过于频繁
+	NOT_LOGIN   = 3 //未登录
 )
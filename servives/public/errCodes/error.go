@@ -0,0 +1,28 @@
+package errCodes
+
+import (
+	"strconv"
+)
+
+// Error 带错误码的错误，消息处理中返回时回复客户端该错误码
+type Error struct {
+	code int32
+}
+
+func New(code int32) *Error {
+	return &Error{code: code}
+}
+
+func (this *Error) Code() int32 {
+	return this.code
+}
+
+func (this *Error) Error() string {
+	return "errCode: " + strconv.Itoa(int(this.code))
+}
+
+var (
+	ErrParam     = New(PARAM_ERROR)
+	ErrRateLimit = New(RATE_LIMIT)
+	ErrNotLogin  = New(NOT_LOGIN)
+)
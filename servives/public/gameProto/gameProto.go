@@ -11,6 +11,16 @@ func init() {
 
 	//connector
 	protos.SetMsg(ID_client_ping_c2s, ClientPingC2S{})
+	protos.SetMsg(ID_client_resumeToken_s2c, ClientResumeTokenS2C{})
+	protos.SetMsg(ID_client_resume_c2s, ClientResumeC2S{})
+	protos.SetMsg(ID_client_resume_s2c, ClientResumeS2C{})
+	protos.SetMsg(ID_client_reliable_s2c, ClientReliableS2C{})
+	protos.SetMsg(ID_client_ack_c2s, ClientAckC2S{})
+	protos.SetMsg(ID_client_ping_s2c, ClientPingS2C{})
+	protos.SetMsg(ID_client_pong_c2s, ClientPongC2S{})
+	protos.SetMsg(ID_client_request_c2s, ClientRequestC2S{})
+	protos.SetMsg(ID_client_response_s2c, ClientResponseS2C{})
+	protos.SetMsg(ID_client_kick_s2c, ClientKickS2C{})
 
 	//login
 	protos.SetMsg(ID_user_login_c2s, UserLoginC2S{})
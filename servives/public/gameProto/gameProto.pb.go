@@ -26,7 +26,8 @@ type ErrorNoticeS2C struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	ErrorCode *int32 `protobuf:"varint,1,req,name=errorCode" json:"errorCode,omitempty"`
+	ErrorCode *int32  `protobuf:"varint,1,req,name=errorCode" json:"errorCode,omitempty"`
+	MsgId     *uint32 `protobuf:"varint,2,opt,name=msgId" json:"msgId,omitempty"`
 }
 
 func (x *ErrorNoticeS2C) Reset() {
@@ -68,6 +69,13 @@ func (x *ErrorNoticeS2C) GetErrorCode() int32 {
 	return 0
 }
 
+func (x *ErrorNoticeS2C) GetMsgId() uint32 {
+	if x != nil && x.MsgId != nil {
+		return *x.MsgId
+	}
+	return 0
+}
+
 //客户端ping(1001)
 type ClientPingC2S struct {
 	state         protoimpl.MessageState
@@ -75,23 +83,409 @@ type ClientPingC2S struct {
 	unknownFields protoimpl.UnknownFields
 }
 
-func (x *ClientPingC2S) Reset() {
-	*x = ClientPingC2S{}
+func (x *ClientPingC2S) Reset() {
+	*x = ClientPingC2S{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gameProto_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ClientPingC2S) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClientPingC2S) ProtoMessage() {}
+
+func (x *ClientPingC2S) ProtoReflect() protoreflect.Message {
+	mi := &file_gameProto_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClientPingC2S.ProtoReflect.Descriptor instead.
+func (*ClientPingC2S) Descriptor() ([]byte, []int) {
+	return file_gameProto_proto_rawDescGZIP(), []int{1}
+}
+
+//断线重连Token(1002)
+type ClientResumeTokenS2C struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Token *string `protobuf:"bytes,1,req,name=token" json:"token,omitempty"`
+}
+
+func (x *ClientResumeTokenS2C) Reset() {
+	*x = ClientResumeTokenS2C{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gameProto_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ClientResumeTokenS2C) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClientResumeTokenS2C) ProtoMessage() {}
+
+func (x *ClientResumeTokenS2C) ProtoReflect() protoreflect.Message {
+	mi := &file_gameProto_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClientResumeTokenS2C.ProtoReflect.Descriptor instead.
+func (*ClientResumeTokenS2C) Descriptor() ([]byte, []int) {
+	return file_gameProto_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ClientResumeTokenS2C) GetToken() string {
+	if x != nil && x.Token != nil {
+		return *x.Token
+	}
+	return ""
+}
+
+//断线重连C2S(1003)
+type ClientResumeC2S struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Token *string `protobuf:"bytes,1,req,name=token" json:"token,omitempty"`
+}
+
+func (x *ClientResumeC2S) Reset() {
+	*x = ClientResumeC2S{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gameProto_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ClientResumeC2S) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClientResumeC2S) ProtoMessage() {}
+
+func (x *ClientResumeC2S) ProtoReflect() protoreflect.Message {
+	mi := &file_gameProto_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClientResumeC2S.ProtoReflect.Descriptor instead.
+func (*ClientResumeC2S) Descriptor() ([]byte, []int) {
+	return file_gameProto_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ClientResumeC2S) GetToken() string {
+	if x != nil && x.Token != nil {
+		return *x.Token
+	}
+	return ""
+}
+
+//断线重连S2C(1004)
+type ClientResumeS2C struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Success *bool `protobuf:"varint,1,req,name=success" json:"success,omitempty"`
+}
+
+func (x *ClientResumeS2C) Reset() {
+	*x = ClientResumeS2C{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gameProto_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ClientResumeS2C) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClientResumeS2C) ProtoMessage() {}
+
+func (x *ClientResumeS2C) ProtoReflect() protoreflect.Message {
+	mi := &file_gameProto_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClientResumeS2C.ProtoReflect.Descriptor instead.
+func (*ClientResumeS2C) Descriptor() ([]byte, []int) {
+	return file_gameProto_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ClientResumeS2C) GetSuccess() bool {
+	if x != nil && x.Success != nil {
+		return *x.Success
+	}
+	return false
+}
+
+//需确认的消息S2C(1005)
+type ClientReliableS2C struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Seq  *uint32 `protobuf:"varint,1,req,name=seq" json:"seq,omitempty"`
+	Data []byte  `protobuf:"bytes,2,req,name=data" json:"data,omitempty"`
+}
+
+func (x *ClientReliableS2C) Reset() {
+	*x = ClientReliableS2C{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gameProto_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ClientReliableS2C) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClientReliableS2C) ProtoMessage() {}
+
+func (x *ClientReliableS2C) ProtoReflect() protoreflect.Message {
+	mi := &file_gameProto_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClientReliableS2C.ProtoReflect.Descriptor instead.
+func (*ClientReliableS2C) Descriptor() ([]byte, []int) {
+	return file_gameProto_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ClientReliableS2C) GetSeq() uint32 {
+	if x != nil && x.Seq != nil {
+		return *x.Seq
+	}
+	return 0
+}
+
+func (x *ClientReliableS2C) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+//消息确认C2S(1006)
+type ClientAckC2S struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Seq *uint32 `protobuf:"varint,1,req,name=seq" json:"seq,omitempty"`
+}
+
+func (x *ClientAckC2S) Reset() {
+	*x = ClientAckC2S{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gameProto_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ClientAckC2S) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClientAckC2S) ProtoMessage() {}
+
+func (x *ClientAckC2S) ProtoReflect() protoreflect.Message {
+	mi := &file_gameProto_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClientAckC2S.ProtoReflect.Descriptor instead.
+func (*ClientAckC2S) Descriptor() ([]byte, []int) {
+	return file_gameProto_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ClientAckC2S) GetSeq() uint32 {
+	if x != nil && x.Seq != nil {
+		return *x.Seq
+	}
+	return 0
+}
+
+//服务器ping S2C(1007)
+type ClientPingS2C struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Time *int64 `protobuf:"varint,1,req,name=time" json:"time,omitempty"`
+}
+
+func (x *ClientPingS2C) Reset() {
+	*x = ClientPingS2C{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gameProto_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ClientPingS2C) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClientPingS2C) ProtoMessage() {}
+
+func (x *ClientPingS2C) ProtoReflect() protoreflect.Message {
+	mi := &file_gameProto_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClientPingS2C.ProtoReflect.Descriptor instead.
+func (*ClientPingS2C) Descriptor() ([]byte, []int) {
+	return file_gameProto_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ClientPingS2C) GetTime() int64 {
+	if x != nil && x.Time != nil {
+		return *x.Time
+	}
+	return 0
+}
+
+//服务器ping回复C2S(1008)
+type ClientPongC2S struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Time *int64 `protobuf:"varint,1,req,name=time" json:"time,omitempty"`
+}
+
+func (x *ClientPongC2S) Reset() {
+	*x = ClientPongC2S{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gameProto_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ClientPongC2S) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClientPongC2S) ProtoMessage() {}
+
+func (x *ClientPongC2S) ProtoReflect() protoreflect.Message {
+	mi := &file_gameProto_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClientPongC2S.ProtoReflect.Descriptor instead.
+func (*ClientPongC2S) Descriptor() ([]byte, []int) {
+	return file_gameProto_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ClientPongC2S) GetTime() int64 {
+	if x != nil && x.Time != nil {
+		return *x.Time
+	}
+	return 0
+}
+
+//带请求序号的消息C2S(1009)，data为原消息
+type ClientRequestC2S struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Seq  *uint32 `protobuf:"varint,1,req,name=seq" json:"seq,omitempty"`
+	Data []byte  `protobuf:"bytes,2,req,name=data" json:"data,omitempty"`
+}
+
+func (x *ClientRequestC2S) Reset() {
+	*x = ClientRequestC2S{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_gameProto_proto_msgTypes[1]
+		mi := &file_gameProto_proto_msgTypes[9]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *ClientPingC2S) String() string {
+func (x *ClientRequestC2S) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ClientPingC2S) ProtoMessage() {}
+func (*ClientRequestC2S) ProtoMessage() {}
 
-func (x *ClientPingC2S) ProtoReflect() protoreflect.Message {
-	mi := &file_gameProto_proto_msgTypes[1]
+func (x *ClientRequestC2S) ProtoReflect() protoreflect.Message {
+	mi := &file_gameProto_proto_msgTypes[9]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -102,9 +496,127 @@ func (x *ClientPingC2S) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ClientPingC2S.ProtoReflect.Descriptor instead.
-func (*ClientPingC2S) Descriptor() ([]byte, []int) {
-	return file_gameProto_proto_rawDescGZIP(), []int{1}
+// Deprecated: Use ClientRequestC2S.ProtoReflect.Descriptor instead.
+func (*ClientRequestC2S) Descriptor() ([]byte, []int) {
+	return file_gameProto_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ClientRequestC2S) GetSeq() uint32 {
+	if x != nil && x.Seq != nil {
+		return *x.Seq
+	}
+	return 0
+}
+
+func (x *ClientRequestC2S) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+//请求的回复S2C(1010)，seq与请求相同
+type ClientResponseS2C struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Seq  *uint32 `protobuf:"varint,1,req,name=seq" json:"seq,omitempty"`
+	Data []byte  `protobuf:"bytes,2,req,name=data" json:"data,omitempty"`
+}
+
+func (x *ClientResponseS2C) Reset() {
+	*x = ClientResponseS2C{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gameProto_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ClientResponseS2C) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClientResponseS2C) ProtoMessage() {}
+
+func (x *ClientResponseS2C) ProtoReflect() protoreflect.Message {
+	mi := &file_gameProto_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClientResponseS2C.ProtoReflect.Descriptor instead.
+func (*ClientResponseS2C) Descriptor() ([]byte, []int) {
+	return file_gameProto_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ClientResponseS2C) GetSeq() uint32 {
+	if x != nil && x.Seq != nil {
+		return *x.Seq
+	}
+	return 0
+}
+
+func (x *ClientResponseS2C) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+//被服务器断开S2C(1011)，收到后客户端不应断线重连
+type ClientKickS2C struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Reason *string `protobuf:"bytes,1,opt,name=reason" json:"reason,omitempty"`
+}
+
+func (x *ClientKickS2C) Reset() {
+	*x = ClientKickS2C{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gameProto_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ClientKickS2C) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClientKickS2C) ProtoMessage() {}
+
+func (x *ClientKickS2C) ProtoReflect() protoreflect.Message {
+	mi := &file_gameProto_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClientKickS2C.ProtoReflect.Descriptor instead.
+func (*ClientKickS2C) Descriptor() ([]byte, []int) {
+	return file_gameProto_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ClientKickS2C) GetReason() string {
+	if x != nil && x.Reason != nil {
+		return *x.Reason
+	}
+	return ""
 }
 
 //用户登录C2S(2001)
@@ -119,7 +631,7 @@ type UserLoginC2S struct {
 func (x *UserLoginC2S) Reset() {
 	*x = UserLoginC2S{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_gameProto_proto_msgTypes[2]
+		mi := &file_gameProto_proto_msgTypes[12]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -132,7 +644,7 @@ func (x *UserLoginC2S) String() string {
 func (*UserLoginC2S) ProtoMessage() {}
 
 func (x *UserLoginC2S) ProtoReflect() protoreflect.Message {
-	mi := &file_gameProto_proto_msgTypes[2]
+	mi := &file_gameProto_proto_msgTypes[12]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -145,7 +657,7 @@ func (x *UserLoginC2S) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UserLoginC2S.ProtoReflect.Descriptor instead.
 func (*UserLoginC2S) Descriptor() ([]byte, []int) {
-	return file_gameProto_proto_rawDescGZIP(), []int{2}
+	return file_gameProto_proto_rawDescGZIP(), []int{12}
 }
 
 func (x *UserLoginC2S) GetAccount() string {
@@ -167,7 +679,7 @@ type UserLoginS2C struct {
 func (x *UserLoginS2C) Reset() {
 	*x = UserLoginS2C{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_gameProto_proto_msgTypes[3]
+		mi := &file_gameProto_proto_msgTypes[13]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -180,7 +692,7 @@ func (x *UserLoginS2C) String() string {
 func (*UserLoginS2C) ProtoMessage() {}
 
 func (x *UserLoginS2C) ProtoReflect() protoreflect.Message {
-	mi := &file_gameProto_proto_msgTypes[3]
+	mi := &file_gameProto_proto_msgTypes[13]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -193,7 +705,7 @@ func (x *UserLoginS2C) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UserLoginS2C.ProtoReflect.Descriptor instead.
 func (*UserLoginS2C) Descriptor() ([]byte, []int) {
-	return file_gameProto_proto_rawDescGZIP(), []int{3}
+	return file_gameProto_proto_rawDescGZIP(), []int{13}
 }
 
 func (x *UserLoginS2C) GetToken() string {
@@ -213,7 +725,7 @@ type UserOtherLoginNoticeS2C struct {
 func (x *UserOtherLoginNoticeS2C) Reset() {
 	*x = UserOtherLoginNoticeS2C{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_gameProto_proto_msgTypes[4]
+		mi := &file_gameProto_proto_msgTypes[14]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -226,7 +738,7 @@ func (x *UserOtherLoginNoticeS2C) String() string {
 func (*UserOtherLoginNoticeS2C) ProtoMessage() {}
 
 func (x *UserOtherLoginNoticeS2C) ProtoReflect() protoreflect.Message {
-	mi := &file_gameProto_proto_msgTypes[4]
+	mi := &file_gameProto_proto_msgTypes[14]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -239,7 +751,7 @@ func (x *UserOtherLoginNoticeS2C) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UserOtherLoginNoticeS2C.ProtoReflect.Descriptor instead.
 func (*UserOtherLoginNoticeS2C) Descriptor() ([]byte, []int) {
-	return file_gameProto_proto_rawDescGZIP(), []int{4}
+	return file_gameProto_proto_rawDescGZIP(), []int{14}
 }
 
 //用户数据
@@ -256,7 +768,7 @@ type UserInfo struct {
 func (x *UserInfo) Reset() {
 	*x = UserInfo{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_gameProto_proto_msgTypes[5]
+		mi := &file_gameProto_proto_msgTypes[15]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -269,7 +781,7 @@ func (x *UserInfo) String() string {
 func (*UserInfo) ProtoMessage() {}
 
 func (x *UserInfo) ProtoReflect() protoreflect.Message {
-	mi := &file_gameProto_proto_msgTypes[5]
+	mi := &file_gameProto_proto_msgTypes[15]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -282,7 +794,7 @@ func (x *UserInfo) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UserInfo.ProtoReflect.Descriptor instead.
 func (*UserInfo) Descriptor() ([]byte, []int) {
-	return file_gameProto_proto_rawDescGZIP(), []int{5}
+	return file_gameProto_proto_rawDescGZIP(), []int{15}
 }
 
 func (x *UserInfo) GetId() uint64 {
@@ -318,7 +830,7 @@ type UserGetInfoC2S struct {
 func (x *UserGetInfoC2S) Reset() {
 	*x = UserGetInfoC2S{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_gameProto_proto_msgTypes[6]
+		mi := &file_gameProto_proto_msgTypes[16]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -331,7 +843,7 @@ func (x *UserGetInfoC2S) String() string {
 func (*UserGetInfoC2S) ProtoMessage() {}
 
 func (x *UserGetInfoC2S) ProtoReflect() protoreflect.Message {
-	mi := &file_gameProto_proto_msgTypes[6]
+	mi := &file_gameProto_proto_msgTypes[16]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -344,7 +856,7 @@ func (x *UserGetInfoC2S) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UserGetInfoC2S.ProtoReflect.Descriptor instead.
 func (*UserGetInfoC2S) Descriptor() ([]byte, []int) {
-	return file_gameProto_proto_rawDescGZIP(), []int{6}
+	return file_gameProto_proto_rawDescGZIP(), []int{16}
 }
 
 func (x *UserGetInfoC2S) GetToken() string {
@@ -366,7 +878,7 @@ type UserGetInfoS2C struct {
 func (x *UserGetInfoS2C) Reset() {
 	*x = UserGetInfoS2C{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_gameProto_proto_msgTypes[7]
+		mi := &file_gameProto_proto_msgTypes[17]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -379,7 +891,7 @@ func (x *UserGetInfoS2C) String() string {
 func (*UserGetInfoS2C) ProtoMessage() {}
 
 func (x *UserGetInfoS2C) ProtoReflect() protoreflect.Message {
-	mi := &file_gameProto_proto_msgTypes[7]
+	mi := &file_gameProto_proto_msgTypes[17]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -392,7 +904,7 @@ func (x *UserGetInfoS2C) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UserGetInfoS2C.ProtoReflect.Descriptor instead.
 func (*UserGetInfoS2C) Descriptor() ([]byte, []int) {
-	return file_gameProto_proto_rawDescGZIP(), []int{7}
+	return file_gameProto_proto_rawDescGZIP(), []int{17}
 }
 
 func (x *UserGetInfoS2C) GetData() *UserInfo {
@@ -414,7 +926,7 @@ type UserJoinChatC2S struct {
 func (x *UserJoinChatC2S) Reset() {
 	*x = UserJoinChatC2S{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_gameProto_proto_msgTypes[8]
+		mi := &file_gameProto_proto_msgTypes[18]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -427,7 +939,7 @@ func (x *UserJoinChatC2S) String() string {
 func (*UserJoinChatC2S) ProtoMessage() {}
 
 func (x *UserJoinChatC2S) ProtoReflect() protoreflect.Message {
-	mi := &file_gameProto_proto_msgTypes[8]
+	mi := &file_gameProto_proto_msgTypes[18]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -440,7 +952,7 @@ func (x *UserJoinChatC2S) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UserJoinChatC2S.ProtoReflect.Descriptor instead.
 func (*UserJoinChatC2S) Descriptor() ([]byte, []int) {
-	return file_gameProto_proto_rawDescGZIP(), []int{8}
+	return file_gameProto_proto_rawDescGZIP(), []int{18}
 }
 
 func (x *UserJoinChatC2S) GetToken() string {
@@ -460,7 +972,7 @@ type UserJoinChatS2C struct {
 func (x *UserJoinChatS2C) Reset() {
 	*x = UserJoinChatS2C{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_gameProto_proto_msgTypes[9]
+		mi := &file_gameProto_proto_msgTypes[19]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -473,7 +985,7 @@ func (x *UserJoinChatS2C) String() string {
 func (*UserJoinChatS2C) ProtoMessage() {}
 
 func (x *UserJoinChatS2C) ProtoReflect() protoreflect.Message {
-	mi := &file_gameProto_proto_msgTypes[9]
+	mi := &file_gameProto_proto_msgTypes[19]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -486,7 +998,7 @@ func (x *UserJoinChatS2C) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UserJoinChatS2C.ProtoReflect.Descriptor instead.
 func (*UserJoinChatS2C) Descriptor() ([]byte, []int) {
-	return file_gameProto_proto_rawDescGZIP(), []int{9}
+	return file_gameProto_proto_rawDescGZIP(), []int{19}
 }
 
 //用户聊天消息C2S(4003)
@@ -501,7 +1013,7 @@ type UserChatC2S struct {
 func (x *UserChatC2S) Reset() {
 	*x = UserChatC2S{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_gameProto_proto_msgTypes[10]
+		mi := &file_gameProto_proto_msgTypes[20]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -514,7 +1026,7 @@ func (x *UserChatC2S) String() string {
 func (*UserChatC2S) ProtoMessage() {}
 
 func (x *UserChatC2S) ProtoReflect() protoreflect.Message {
-	mi := &file_gameProto_proto_msgTypes[10]
+	mi := &file_gameProto_proto_msgTypes[20]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -527,7 +1039,7 @@ func (x *UserChatC2S) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UserChatC2S.ProtoReflect.Descriptor instead.
 func (*UserChatC2S) Descriptor() ([]byte, []int) {
-	return file_gameProto_proto_rawDescGZIP(), []int{10}
+	return file_gameProto_proto_rawDescGZIP(), []int{20}
 }
 
 func (x *UserChatC2S) GetMsg() string {
@@ -551,7 +1063,7 @@ type UserChatNoticeS2C struct {
 func (x *UserChatNoticeS2C) Reset() {
 	*x = UserChatNoticeS2C{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_gameProto_proto_msgTypes[11]
+		mi := &file_gameProto_proto_msgTypes[21]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -564,7 +1076,7 @@ func (x *UserChatNoticeS2C) String() string {
 func (*UserChatNoticeS2C) ProtoMessage() {}
 
 func (x *UserChatNoticeS2C) ProtoReflect() protoreflect.Message {
-	mi := &file_gameProto_proto_msgTypes[11]
+	mi := &file_gameProto_proto_msgTypes[21]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -577,7 +1089,7 @@ func (x *UserChatNoticeS2C) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UserChatNoticeS2C.ProtoReflect.Descriptor instead.
 func (*UserChatNoticeS2C) Descriptor() ([]byte, []int) {
-	return file_gameProto_proto_rawDescGZIP(), []int{11}
+	return file_gameProto_proto_rawDescGZIP(), []int{21}
 }
 
 func (x *UserChatNoticeS2C) GetUserId() uint64 {
@@ -605,41 +1117,72 @@ var File_gameProto_proto protoreflect.FileDescriptor
 
 var file_gameProto_proto_rawDesc = []byte{
 	0x0a, 0x0f, 0x67, 0x61, 0x6d, 0x65, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x70, 0x72, 0x6f, 0x74,
-	0x6f, 0x22, 0x30, 0x0a, 0x10, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x5f, 0x6e, 0x6f, 0x74, 0x69, 0x63,
+	0x6f, 0x22, 0x46, 0x0a, 0x10, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x5f, 0x6e, 0x6f, 0x74, 0x69, 0x63,
 	0x65, 0x5f, 0x73, 0x32, 0x63, 0x12, 0x1c, 0x0a, 0x09, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x43, 0x6f,
 	0x64, 0x65, 0x18, 0x01, 0x20, 0x02, 0x28, 0x05, 0x52, 0x09, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x43,
-	0x6f, 0x64, 0x65, 0x22, 0x11, 0x0a, 0x0f, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x5f, 0x70, 0x69,
-	0x6e, 0x67, 0x5f, 0x63, 0x32, 0x73, 0x22, 0x2a, 0x0a, 0x0e, 0x75, 0x73, 0x65, 0x72, 0x5f, 0x6c,
-	0x6f, 0x67, 0x69, 0x6e, 0x5f, 0x63, 0x32, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x61, 0x63, 0x63, 0x6f,
-	0x75, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x02, 0x28, 0x09, 0x52, 0x07, 0x61, 0x63, 0x63, 0x6f, 0x75,
-	0x6e, 0x74, 0x22, 0x26, 0x0a, 0x0e, 0x75, 0x73, 0x65, 0x72, 0x5f, 0x6c, 0x6f, 0x67, 0x69, 0x6e,
-	0x5f, 0x73, 0x32, 0x63, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x01, 0x20,
-	0x02, 0x28, 0x09, 0x52, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x22, 0x1c, 0x0a, 0x1a, 0x75, 0x73,
-	0x65, 0x72, 0x5f, 0x6f, 0x74, 0x68, 0x65, 0x72, 0x4c, 0x6f, 0x67, 0x69, 0x6e, 0x5f, 0x6e, 0x6f,
-	0x74, 0x69, 0x63, 0x65, 0x5f, 0x73, 0x32, 0x63, 0x22, 0x44, 0x0a, 0x08, 0x75, 0x73, 0x65, 0x72,
-	0x49, 0x6e, 0x66, 0x6f, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x02, 0x28, 0x04,
-	0x52, 0x02, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x02,
-	0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x6d, 0x6f, 0x6e, 0x65,
-	0x79, 0x18, 0x03, 0x20, 0x02, 0x28, 0x05, 0x52, 0x05, 0x6d, 0x6f, 0x6e, 0x65, 0x79, 0x22, 0x28,
-	0x0a, 0x10, 0x75, 0x73, 0x65, 0x72, 0x5f, 0x67, 0x65, 0x74, 0x49, 0x6e, 0x66, 0x6f, 0x5f, 0x63,
-	0x32, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x01, 0x20, 0x02, 0x28,
-	0x09, 0x52, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x22, 0x31, 0x0a, 0x10, 0x75, 0x73, 0x65, 0x72,
-	0x5f, 0x67, 0x65, 0x74, 0x49, 0x6e, 0x66, 0x6f, 0x5f, 0x73, 0x32, 0x63, 0x12, 0x1d, 0x0a, 0x04,
-	0x64, 0x61, 0x74, 0x61, 0x18, 0x01, 0x20, 0x02, 0x28, 0x0b, 0x32, 0x09, 0x2e, 0x75, 0x73, 0x65,
-	0x72, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x22, 0x29, 0x0a, 0x11, 0x75,
-	0x73, 0x65, 0x72, 0x5f, 0x6a, 0x6f, 0x69, 0x6e, 0x43, 0x68, 0x61, 0x74, 0x5f, 0x63, 0x32, 0x73,
-	0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x01, 0x20, 0x02, 0x28, 0x09, 0x52,
-	0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x22, 0x13, 0x0a, 0x11, 0x75, 0x73, 0x65, 0x72, 0x5f, 0x6a,
-	0x6f, 0x69, 0x6e, 0x43, 0x68, 0x61, 0x74, 0x5f, 0x73, 0x32, 0x63, 0x22, 0x21, 0x0a, 0x0d, 0x75,
-	0x73, 0x65, 0x72, 0x5f, 0x63, 0x68, 0x61, 0x74, 0x5f, 0x63, 0x32, 0x73, 0x12, 0x10, 0x0a, 0x03,
-	0x6d, 0x73, 0x67, 0x18, 0x01, 0x20, 0x02, 0x28, 0x09, 0x52, 0x03, 0x6d, 0x73, 0x67, 0x22, 0x5c,
-	0x0a, 0x14, 0x75, 0x73, 0x65, 0x72, 0x5f, 0x63, 0x68, 0x61, 0x74, 0x5f, 0x6e, 0x6f, 0x74, 0x69,
-	0x63, 0x65, 0x5f, 0x73, 0x32, 0x63, 0x12, 0x16, 0x0a, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x64,
-	0x18, 0x01, 0x20, 0x02, 0x28, 0x04, 0x52, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x64, 0x12, 0x1a,
-	0x0a, 0x08, 0x75, 0x73, 0x65, 0x72, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x02, 0x28, 0x09,
-	0x52, 0x08, 0x75, 0x73, 0x65, 0x72, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x6d, 0x73,
-	0x67, 0x18, 0x03, 0x20, 0x02, 0x28, 0x09, 0x52, 0x03, 0x6d, 0x73, 0x67, 0x42, 0x0d, 0x5a, 0x0b,
-	0x2e, 0x3b, 0x67, 0x61, 0x6d, 0x65, 0x50, 0x72, 0x6f, 0x74, 0x6f,
+	0x6f, 0x64, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x6d, 0x73, 0x67, 0x49, 0x64, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0d, 0x52, 0x05, 0x6d, 0x73, 0x67, 0x49, 0x64, 0x22, 0x11, 0x0a, 0x0f, 0x63, 0x6c, 0x69,
+	0x65, 0x6e, 0x74, 0x5f, 0x70, 0x69, 0x6e, 0x67, 0x5f, 0x63, 0x32, 0x73, 0x22, 0x2e, 0x0a, 0x16,
+	0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x5f, 0x72, 0x65, 0x73, 0x75, 0x6d, 0x65, 0x54, 0x6f, 0x6b,
+	0x65, 0x6e, 0x5f, 0x73, 0x32, 0x63, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18,
+	0x01, 0x20, 0x02, 0x28, 0x09, 0x52, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x22, 0x29, 0x0a, 0x11,
+	0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x5f, 0x72, 0x65, 0x73, 0x75, 0x6d, 0x65, 0x5f, 0x63, 0x32,
+	0x73, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x01, 0x20, 0x02, 0x28, 0x09,
+	0x52, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x22, 0x2d, 0x0a, 0x11, 0x63, 0x6c, 0x69, 0x65, 0x6e,
+	0x74, 0x5f, 0x72, 0x65, 0x73, 0x75, 0x6d, 0x65, 0x5f, 0x73, 0x32, 0x63, 0x12, 0x18, 0x0a, 0x07,
+	0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x02, 0x28, 0x08, 0x52, 0x07, 0x73,
+	0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x22, 0x3b, 0x0a, 0x13, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74,
+	0x5f, 0x72, 0x65, 0x6c, 0x69, 0x61, 0x62, 0x6c, 0x65, 0x5f, 0x73, 0x32, 0x63, 0x12, 0x10, 0x0a,
+	0x03, 0x73, 0x65, 0x71, 0x18, 0x01, 0x20, 0x02, 0x28, 0x0d, 0x52, 0x03, 0x73, 0x65, 0x71, 0x12,
+	0x12, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x02, 0x20, 0x02, 0x28, 0x0c, 0x52, 0x04, 0x64,
+	0x61, 0x74, 0x61, 0x22, 0x22, 0x0a, 0x0e, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x5f, 0x61, 0x63,
+	0x6b, 0x5f, 0x63, 0x32, 0x73, 0x12, 0x10, 0x0a, 0x03, 0x73, 0x65, 0x71, 0x18, 0x01, 0x20, 0x02,
+	0x28, 0x0d, 0x52, 0x03, 0x73, 0x65, 0x71, 0x22, 0x25, 0x0a, 0x0f, 0x63, 0x6c, 0x69, 0x65, 0x6e,
+	0x74, 0x5f, 0x70, 0x69, 0x6e, 0x67, 0x5f, 0x73, 0x32, 0x63, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x69,
+	0x6d, 0x65, 0x18, 0x01, 0x20, 0x02, 0x28, 0x03, 0x52, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x22, 0x25,
+	0x0a, 0x0f, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x5f, 0x70, 0x6f, 0x6e, 0x67, 0x5f, 0x63, 0x32,
+	0x73, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x02, 0x28, 0x03, 0x52,
+	0x04, 0x74, 0x69, 0x6d, 0x65, 0x22, 0x3a, 0x0a, 0x12, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x5f,
+	0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x5f, 0x63, 0x32, 0x73, 0x12, 0x10, 0x0a, 0x03, 0x73,
+	0x65, 0x71, 0x18, 0x01, 0x20, 0x02, 0x28, 0x0d, 0x52, 0x03, 0x73, 0x65, 0x71, 0x12, 0x12, 0x0a,
+	0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x02, 0x20, 0x02, 0x28, 0x0c, 0x52, 0x04, 0x64, 0x61, 0x74,
+	0x61, 0x22, 0x3b, 0x0a, 0x13, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x5f, 0x72, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x5f, 0x73, 0x32, 0x63, 0x12, 0x10, 0x0a, 0x03, 0x73, 0x65, 0x71, 0x18,
+	0x01, 0x20, 0x02, 0x28, 0x0d, 0x52, 0x03, 0x73, 0x65, 0x71, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61,
+	0x74, 0x61, 0x18, 0x02, 0x20, 0x02, 0x28, 0x0c, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x22, 0x29,
+	0x0a, 0x0f, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x5f, 0x6b, 0x69, 0x63, 0x6b, 0x5f, 0x73, 0x32,
+	0x63, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x22, 0x2a, 0x0a, 0x0e, 0x75, 0x73, 0x65,
+	0x72, 0x5f, 0x6c, 0x6f, 0x67, 0x69, 0x6e, 0x5f, 0x63, 0x32, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x61,
+	0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x02, 0x28, 0x09, 0x52, 0x07, 0x61, 0x63,
+	0x63, 0x6f, 0x75, 0x6e, 0x74, 0x22, 0x26, 0x0a, 0x0e, 0x75, 0x73, 0x65, 0x72, 0x5f, 0x6c, 0x6f,
+	0x67, 0x69, 0x6e, 0x5f, 0x73, 0x32, 0x63, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e,
+	0x18, 0x01, 0x20, 0x02, 0x28, 0x09, 0x52, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x22, 0x1c, 0x0a,
+	0x1a, 0x75, 0x73, 0x65, 0x72, 0x5f, 0x6f, 0x74, 0x68, 0x65, 0x72, 0x4c, 0x6f, 0x67, 0x69, 0x6e,
+	0x5f, 0x6e, 0x6f, 0x74, 0x69, 0x63, 0x65, 0x5f, 0x73, 0x32, 0x63, 0x22, 0x44, 0x0a, 0x08, 0x75,
+	0x73, 0x65, 0x72, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20,
+	0x02, 0x28, 0x04, 0x52, 0x02, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18,
+	0x02, 0x20, 0x02, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x6d,
+	0x6f, 0x6e, 0x65, 0x79, 0x18, 0x03, 0x20, 0x02, 0x28, 0x05, 0x52, 0x05, 0x6d, 0x6f, 0x6e, 0x65,
+	0x79, 0x22, 0x28, 0x0a, 0x10, 0x75, 0x73, 0x65, 0x72, 0x5f, 0x67, 0x65, 0x74, 0x49, 0x6e, 0x66,
+	0x6f, 0x5f, 0x63, 0x32, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x01,
+	0x20, 0x02, 0x28, 0x09, 0x52, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x22, 0x31, 0x0a, 0x10, 0x75,
+	0x73, 0x65, 0x72, 0x5f, 0x67, 0x65, 0x74, 0x49, 0x6e, 0x66, 0x6f, 0x5f, 0x73, 0x32, 0x63, 0x12,
+	0x1d, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x01, 0x20, 0x02, 0x28, 0x0b, 0x32, 0x09, 0x2e,
+	0x75, 0x73, 0x65, 0x72, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x22, 0x29,
+	0x0a, 0x11, 0x75, 0x73, 0x65, 0x72, 0x5f, 0x6a, 0x6f, 0x69, 0x6e, 0x43, 0x68, 0x61, 0x74, 0x5f,
+	0x63, 0x32, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x01, 0x20, 0x02,
+	0x28, 0x09, 0x52, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x22, 0x13, 0x0a, 0x11, 0x75, 0x73, 0x65,
+	0x72, 0x5f, 0x6a, 0x6f, 0x69, 0x6e, 0x43, 0x68, 0x61, 0x74, 0x5f, 0x73, 0x32, 0x63, 0x22, 0x21,
+	0x0a, 0x0d, 0x75, 0x73, 0x65, 0x72, 0x5f, 0x63, 0x68, 0x61, 0x74, 0x5f, 0x63, 0x32, 0x73, 0x12,
+	0x10, 0x0a, 0x03, 0x6d, 0x73, 0x67, 0x18, 0x01, 0x20, 0x02, 0x28, 0x09, 0x52, 0x03, 0x6d, 0x73,
+	0x67, 0x22, 0x5c, 0x0a, 0x14, 0x75, 0x73, 0x65, 0x72, 0x5f, 0x63, 0x68, 0x61, 0x74, 0x5f, 0x6e,
+	0x6f, 0x74, 0x69, 0x63, 0x65, 0x5f, 0x73, 0x32, 0x63, 0x12, 0x16, 0x0a, 0x06, 0x75, 0x73, 0x65,
+	0x72, 0x49, 0x64, 0x18, 0x01, 0x20, 0x02, 0x28, 0x04, 0x52, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49,
+	0x64, 0x12, 0x1a, 0x0a, 0x08, 0x75, 0x73, 0x65, 0x72, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20,
+	0x02, 0x28, 0x09, 0x52, 0x08, 0x75, 0x73, 0x65, 0x72, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x10, 0x0a,
+	0x03, 0x6d, 0x73, 0x67, 0x18, 0x03, 0x20, 0x02, 0x28, 0x09, 0x52, 0x03, 0x6d, 0x73, 0x67, 0x42,
+	0x0d, 0x5a, 0x0b, 0x2e, 0x3b, 0x67, 0x61, 0x6d, 0x65, 0x50, 0x72, 0x6f, 0x74, 0x6f,
 }
 
 var (
@@ -654,28 +1197,38 @@ func file_gameProto_proto_rawDescGZIP() []byte {
 	return file_gameProto_proto_rawDescData
 }
 
-var file_gameProto_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
+var file_gameProto_proto_msgTypes = make([]protoimpl.MessageInfo, 22)
 var file_gameProto_proto_goTypes = []interface{}{
 	(*ErrorNoticeS2C)(nil),          // 0: error_notice_s2c
 	(*ClientPingC2S)(nil),           // 1: client_ping_c2s
-	(*UserLoginC2S)(nil),            // 2: user_login_c2s
-	(*UserLoginS2C)(nil),            // 3: user_login_s2c
-	(*UserOtherLoginNoticeS2C)(nil), // 4: user_otherLogin_notice_s2c
-	(*UserInfo)(nil),                // 5: userInfo
-	(*UserGetInfoC2S)(nil),          // 6: user_getInfo_c2s
-	(*UserGetInfoS2C)(nil),          // 7: user_getInfo_s2c
-	(*UserJoinChatC2S)(nil),         // 8: user_joinChat_c2s
-	(*UserJoinChatS2C)(nil),         // 9: user_joinChat_s2c
-	(*UserChatC2S)(nil),             // 10: user_chat_c2s
-	(*UserChatNoticeS2C)(nil),       // 11: user_chat_notice_s2c
+	(*ClientResumeTokenS2C)(nil),    // 2: client_resumeToken_s2c
+	(*ClientResumeC2S)(nil),         // 3: client_resume_c2s
+	(*ClientResumeS2C)(nil),         // 4: client_resume_s2c
+	(*ClientReliableS2C)(nil),       // 5: client_reliable_s2c
+	(*ClientAckC2S)(nil),            // 6: client_ack_c2s
+	(*ClientPingS2C)(nil),           // 7: client_ping_s2c
+	(*ClientPongC2S)(nil),           // 8: client_pong_c2s
+	(*ClientRequestC2S)(nil),        // 9: client_request_c2s
+	(*ClientResponseS2C)(nil),       // 10: client_response_s2c
+	(*ClientKickS2C)(nil),           // 11: client_kick_s2c
+	(*UserLoginC2S)(nil),            // 12: user_login_c2s
+	(*UserLoginS2C)(nil),            // 13: user_login_s2c
+	(*UserOtherLoginNoticeS2C)(nil), // 14: user_otherLogin_notice_s2c
+	(*UserInfo)(nil),                // 15: userInfo
+	(*UserGetInfoC2S)(nil),          // 16: user_getInfo_c2s
+	(*UserGetInfoS2C)(nil),          // 17: user_getInfo_s2c
+	(*UserJoinChatC2S)(nil),         // 18: user_joinChat_c2s
+	(*UserJoinChatS2C)(nil),         // 19: user_joinChat_s2c
+	(*UserChatC2S)(nil),             // 20: user_chat_c2s
+	(*UserChatNoticeS2C)(nil),       // 21: user_chat_notice_s2c
 }
 var file_gameProto_proto_depIdxs = []int32{
-	5, // 0: user_getInfo_s2c.data:type_name -> userInfo
-	1, // [1:1] is the sub-list for method output_type
-	1, // [1:1] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
+	15, // 0: user_getInfo_s2c.data:type_name -> userInfo
+	1,  // [1:1] is the sub-list for method output_type
+	1,  // [1:1] is the sub-list for method input_type
+	1,  // [1:1] is the sub-list for extension type_name
+	1,  // [1:1] is the sub-list for extension extendee
+	0,  // [0:1] is the sub-list for field type_name
 }
 
 func init() { file_gameProto_proto_init() }
@@ -709,7 +1262,7 @@ func file_gameProto_proto_init() {
 			}
 		}
 		file_gameProto_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UserLoginC2S); i {
+			switch v := v.(*ClientResumeTokenS2C); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -721,7 +1274,7 @@ func file_gameProto_proto_init() {
 			}
 		}
 		file_gameProto_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UserLoginS2C); i {
+			switch v := v.(*ClientResumeC2S); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -733,7 +1286,7 @@ func file_gameProto_proto_init() {
 			}
 		}
 		file_gameProto_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UserOtherLoginNoticeS2C); i {
+			switch v := v.(*ClientResumeS2C); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -745,7 +1298,7 @@ func file_gameProto_proto_init() {
 			}
 		}
 		file_gameProto_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UserInfo); i {
+			switch v := v.(*ClientReliableS2C); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -757,7 +1310,7 @@ func file_gameProto_proto_init() {
 			}
 		}
 		file_gameProto_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UserGetInfoC2S); i {
+			switch v := v.(*ClientAckC2S); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -769,7 +1322,7 @@ func file_gameProto_proto_init() {
 			}
 		}
 		file_gameProto_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UserGetInfoS2C); i {
+			switch v := v.(*ClientPingS2C); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -781,7 +1334,7 @@ func file_gameProto_proto_init() {
 			}
 		}
 		file_gameProto_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UserJoinChatC2S); i {
+			switch v := v.(*ClientPongC2S); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -793,7 +1346,7 @@ func file_gameProto_proto_init() {
 			}
 		}
 		file_gameProto_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UserJoinChatS2C); i {
+			switch v := v.(*ClientRequestC2S); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -805,7 +1358,7 @@ func file_gameProto_proto_init() {
 			}
 		}
 		file_gameProto_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UserChatC2S); i {
+			switch v := v.(*ClientResponseS2C); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -817,6 +1370,126 @@ func file_gameProto_proto_init() {
 			}
 		}
 		file_gameProto_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ClientKickS2C); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gameProto_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UserLoginC2S); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gameProto_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UserLoginS2C); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gameProto_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UserOtherLoginNoticeS2C); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gameProto_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UserInfo); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gameProto_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UserGetInfoC2S); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gameProto_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UserGetInfoS2C); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gameProto_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UserJoinChatC2S); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gameProto_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UserJoinChatS2C); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gameProto_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UserChatC2S); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gameProto_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*UserChatNoticeS2C); i {
 			case 0:
 				return &v.state
@@ -835,7 +1508,7 @@ func file_gameProto_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_gameProto_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   12,
+			NumMessages:   22,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
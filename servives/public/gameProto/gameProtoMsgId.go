@@ -3,7 +3,17 @@ package gameProto
 const (
 	ID_error_notice_s2c = 500
 
-	ID_client_ping_c2s = 1001
+	ID_client_ping_c2s        = 1001
+	ID_client_resumeToken_s2c = 1002
+	ID_client_resume_c2s      = 1003
+	ID_client_resume_s2c      = 1004
+	ID_client_reliable_s2c    = 1005
+	ID_client_ack_c2s         = 1006
+	ID_client_ping_s2c        = 1007
+	ID_client_pong_c2s        = 1008
+	ID_client_request_c2s     = 1009
+	ID_client_response_s2c    = 1010
+	ID_client_kick_s2c        = 1011
 
 	ID_user_login_c2s             = 2001
 	ID_user_login_s2c             = 2002
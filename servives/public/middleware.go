@@ -0,0 +1,67 @@
+package public
+
+import (
+	"errors"
+	"time"
+
+	"github.com/yicaoyimuys/GoGameServer/core/consts"
+	. "github.com/yicaoyimuys/GoGameServer/core/libs"
+	"github.com/yicaoyimuys/GoGameServer/core/libs/sessions"
+	"github.com/yicaoyimuys/GoGameServer/core/messages"
+	"github.com/yicaoyimuys/GoGameServer/servives/public/errCodes"
+	"go.uber.org/zap"
+
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	//消息处理超过该时间输出警告日志
+	slowMsgTime = 100 * time.Millisecond
+)
+
+// ErrorCode 错误对应的错误码，不是errCodes.Error时为系统错误
+func ErrorCode(err error) int32 {
+	var codeErr *errCodes.Error
+	if errors.As(err, &codeErr) {
+		return codeErr.Code()
+	}
+	if errors.Is(err, messages.ErrNotLogin) {
+		return errCodes.NOT_LOGIN
+	}
+	return consts.ErrCode_SystemError
+}
+
+// ReplyErrorToClient 消息处理返回错误时回复客户端错误码，由各服务SetIpcServerErrorHandle设置
+func ReplyErrorToClient(clientSession *sessions.BackSession, err error) {
+	errorCode := ErrorCode(err)
+	if errorCode == consts.ErrCode_SystemError {
+		ERR("消息处理错误", zap.Uint16("MsgId", clientSession.RequestMsgId()), zap.Error(err))
+	}
+	SendErrorMsgToClient(clientSession, errorCode)
+}
+
+// UseDefaultMiddleware 后端服务对所有消息使用的中间件：异常恢复、处理时间统计
+func UseDefaultMiddleware() {
+	messages.UseIpcServerMiddleware(Recover(), messages.TimingMiddleware(slowMsgTime))
+}
+
+// Recover 消息处理异常时回复客户端系统错误
+func Recover() messages.IpcServerMiddleware {
+	return messages.RecoverMiddleware(func(clientSession *sessions.BackSession, recover interface{}) {
+		SendErrorMsgToClient(clientSession, consts.ErrCode_SystemError)
+	})
+}
+
+// Validate check返回false时回复PARAM_ERROR
+func Validate(check func(msgData proto.Message) bool) messages.IpcServerMiddleware {
+	return messages.ValidateMiddleware(check, func(clientSession *sessions.BackSession) {
+		SendErrorMsgToClient(clientSession, errCodes.PARAM_ERROR)
+	})
+}
+
+// RateLimit 每个Session单独限流，超出时回复RATE_LIMIT
+func RateLimit(rate float64, burst float64) messages.IpcServerMiddleware {
+	return messages.RateLimitMiddleware(rate, burst, func(clientSession *sessions.BackSession) {
+		SendErrorMsgToClient(clientSession, errCodes.RATE_LIMIT)
+	})
+}
@@ -1,6 +1,11 @@
 package redisKeys
 
 const (
-	ServerInfo = "server.info"
-	DbUser     = "db.user."
+	ServerInfo       = "server.info"
+	DbUser           = "db.user."
+	ResumeSession    = "resume.session."
+	ResumeSessionMsg = "resume.session.msg."
+	//断线重连到其他connector后，原connector收到的后端服务器切换通知数量
+	ResumeSessionMoved = "resume.session.moved."
+	RouteUser          = "route.user."
 )
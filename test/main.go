@@ -1,7 +1,6 @@
 package main
 
 import (
-	"encoding/binary"
 	"encoding/json"
 	"io"
 	"net"
@@ -13,6 +12,7 @@ import (
 	"github.com/yicaoyimuys/GoGameServer/core/consts"
 	. "github.com/yicaoyimuys/GoGameServer/core/libs"
 	"github.com/yicaoyimuys/GoGameServer/core/libs/array"
+	"github.com/yicaoyimuys/GoGameServer/core/libs/frame"
 	"github.com/yicaoyimuys/GoGameServer/core/libs/hash"
 	"github.com/yicaoyimuys/GoGameServer/core/libs/protos"
 	"github.com/yicaoyimuys/GoGameServer/core/libs/random"
@@ -28,6 +28,10 @@ var (
 	servers      []string
 	connectNum   = 1
 	userAccounts = []string{}
+
+	//握手协商的压缩方式，设置为frame.Compress_None可验证不压缩模式
+	compressType      = frame.Compress_Snappy
+	compressThreshold = 128
 )
 
 func main() {
@@ -104,8 +108,15 @@ func startConnect(account string) {
 	INFO("连接成功", zap.String("Account", account))
 
 	client := &clientSession{
-		con:     conn,
-		account: account,
+		con:      conn,
+		account:  account,
+		protocol: frame.NewProtocol(0, compressThreshold),
+	}
+
+	if err := client.handshake(); err != nil {
+		ERR("握手失败", zap.String("Account", account), zap.Error(err))
+		client.close()
+		return
 	}
 
 	go client.receiveMsg()
@@ -115,8 +126,12 @@ func startConnect(account string) {
 
 type clientSession struct {
 	con         *net.TCPConn
+	protocol    *frame.Protocol
 	account     string
 	token       string
+	resumeToken string
+	reliableSeq uint32
+	requestSeq  uint32
 	pingTimerId *timer.TimerEvent
 	chatTimerId *timer.TimerEvent
 	closeFlag   int32
@@ -140,12 +155,32 @@ func (this *clientSession) isClose() bool {
 	return atomic.LoadInt32(&this.closeFlag) == 1
 }
 
+// 握手
+func (this *clientSession) handshake() error {
+	request := frame.PackHandshake(frame.Version_Compress, compressType)
+	if _, err := this.con.Write(request); err != nil {
+		return err
+	}
+
+	response := make([]byte, len(request))
+	if _, err := io.ReadFull(this.con, response); err != nil {
+		return err
+	}
+	version, compress, err := frame.ParseHandshake(response)
+	if err != nil {
+		return err
+	}
+	this.protocol.SetVersion(version, compress)
+	INFO("握手成功", zap.String("Account", this.account), zap.Uint8("Version", version), zap.Uint8("Compress", compress))
+	return nil
+}
+
 // 平台登录
 func (this *clientSession) login() {
 	msg := &gameProto.UserLoginC2S{
 		Account: protos.String(this.account),
 	}
-	this.sendMsg(msg)
+	this.sendRequest(msg)
 }
 
 // 获取用户数据
@@ -153,7 +188,7 @@ func (this *clientSession) getInfo() {
 	msg := &gameProto.UserGetInfoC2S{
 		Token: protos.String(this.token),
 	}
-	this.sendMsg(msg)
+	this.sendRequest(msg)
 }
 
 // 心跳
@@ -191,15 +226,15 @@ func (this *clientSession) receiveMsg() {
 		}
 
 		//消息头
-		msgHead := make([]byte, 2)
+		msgHead := make([]byte, this.protocol.HeadLen())
 		if _, err := io.ReadFull(this.con, msgHead); err != nil {
 			ERR("读取消息头失败", zap.String("Account", this.account), zap.Error(err))
 			break
 		}
 
 		//消息体长度
-		msgLen := binary.BigEndian.Uint16(msgHead)
-		if msgLen <= 0 {
+		msgLen, compressed, err := this.protocol.ParseHead(msgHead)
+		if err != nil || msgLen <= 0 {
 			ERR("消息体长度错误", zap.String("Account", this.account))
 			break
 		}
@@ -211,13 +246,20 @@ func (this *clientSession) receiveMsg() {
 			break
 		}
 
+		//解压
+		msgBody, err = this.protocol.Unpack(msgBody, compressed)
+		if err != nil {
+			ERR("消息解压失败", zap.String("Account", this.account), zap.Error(err))
+			break
+		}
+
 		//消息解析
 		protoMsg := protos.UnmarshalProtoMsg(msgBody)
 		if protoMsg == protos.NullProtoMsg {
 			ERR("收到错误消息ID", zap.String("Account", this.account), zap.Uint16("MsgId", protos.UnmarshalProtoId(msgBody)))
 			break
 		}
-		DEBUG("收到消息ID", zap.String("Account", this.account), zap.Uint16("MsgId", protoMsg.ID))
+		DEBUG("收到消息ID", zap.String("Account", this.account), zap.Uint16("MsgId", protoMsg.ID), zap.Bool("Compressed", compressed))
 
 		//消息处理
 		this.handleMsg(protoMsg.ID, protoMsg.Body)
@@ -228,7 +270,34 @@ func (this *clientSession) receiveMsg() {
 func (this *clientSession) handleMsg(msgId uint16, msgData proto.Message) {
 	defer stack.TryError()
 
-	if msgId == gameProto.ID_user_login_s2c {
+	if msgId == gameProto.ID_client_resumeToken_s2c {
+		//断线重连Token
+		data := msgData.(*gameProto.ClientResumeTokenS2C)
+		this.resumeToken = data.GetToken()
+	} else if msgId == gameProto.ID_client_response_s2c {
+		//带序号请求的回复
+		data := msgData.(*gameProto.ClientResponseS2C)
+		protoMsg := protos.UnmarshalProtoMsg(data.GetData())
+		if protoMsg != protos.NullProtoMsg {
+			DEBUG("收到请求回复", zap.String("Account", this.account), zap.Uint32("Seq", data.GetSeq()), zap.Uint16("MsgId", protoMsg.ID))
+			this.handleMsg(protoMsg.ID, protoMsg.Body)
+		}
+	} else if msgId == gameProto.ID_client_reliable_s2c {
+		//需确认的消息
+		data := msgData.(*gameProto.ClientReliableS2C)
+		this.handleReliableMsg(data.GetSeq(), data.GetData())
+	} else if msgId == gameProto.ID_client_kick_s2c {
+		//被服务器断开，不再断线重连
+		data := msgData.(*gameProto.ClientKickS2C)
+		this.resumeToken = ""
+		INFO("被服务器断开", zap.String("Account", this.account), zap.String("Reason", data.GetReason()))
+	} else if msgId == gameProto.ID_client_ping_s2c {
+		//服务器ping，原样返回时间
+		data := msgData.(*gameProto.ClientPingS2C)
+		this.sendMsg(&gameProto.ClientPongC2S{
+			Time: protos.Int64(data.GetTime()),
+		})
+	} else if msgId == gameProto.ID_user_login_s2c {
 		//登录成功
 		data := msgData.(*gameProto.UserLoginS2C)
 		this.token = data.GetToken()
@@ -265,6 +334,34 @@ func (this *clientSession) handleMsg(msgId uint16, msgData proto.Message) {
 	}
 }
 
+// 按序号处理需确认的消息，重复或乱序的消息丢弃，等待服务器重发
+func (this *clientSession) handleReliableMsg(seq uint32, data []byte) {
+	if seq == this.reliableSeq+1 {
+		this.reliableSeq = seq
+		protoMsg := protos.UnmarshalProtoMsg(data)
+		if protoMsg != protos.NullProtoMsg {
+			this.handleMsg(protoMsg.ID, protoMsg.Body)
+		}
+	} else {
+		DEBUG("丢弃重复消息", zap.String("Account", this.account), zap.Uint32("Seq", seq), zap.Uint32("ReliableSeq", this.reliableSeq))
+	}
+
+	//确认已收到的最大序号
+	msg := &gameProto.ClientAckC2S{
+		Seq: protos.Uint32(this.reliableSeq),
+	}
+	this.sendMsg(msg)
+}
+
+// 发送带序号的请求，回复中带有相同的序号
+func (this *clientSession) sendRequest(msg proto.Message) {
+	msg = &gameProto.ClientRequestC2S{
+		Seq:  protos.Uint32(atomic.AddUint32(&this.requestSeq, 1)),
+		Data: protos.MarshalProtoMsg(msg),
+	}
+	this.sendMsg(msg)
+}
+
 func (this *clientSession) sendMsg(msg proto.Message) {
 	if this.isClose() {
 		return
@@ -276,11 +373,11 @@ func (this *clientSession) sendMsg(msg proto.Message) {
 	defer this.sendMutex.Unlock()
 
 	msgBytes := protos.MarshalProtoMsg(msg)
-
-	msgLen := uint16(len(msgBytes))
-	sendMsg := make([]byte, msgLen+2)
-	binary.BigEndian.PutUint16(sendMsg[:2], msgLen)
-	copy(sendMsg[2:], msgBytes)
+	sendMsg, err := this.protocol.Pack(msgBytes)
+	if err != nil {
+		ERR("消息打包失败", zap.String("Account", this.account), zap.Error(err))
+		return
+	}
 
 	this.con.Write(sendMsg)
 }